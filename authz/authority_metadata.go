@@ -2,9 +2,11 @@ package authz
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -29,6 +31,18 @@ var outgoingAuthorityMetadataAllowlist = map[string]struct{}{
 	constant.ClientType:    {},
 	constant.ClientName:    {},
 	constant.ClientVersion: {},
+	// 一致性令牌需要随读请求继续传给下游，保证整条调用链都按同一版本选择只读副本。
+	constant.ConsistencyToken: {},
+}
+
+// OutgoingMetadataAllowlist 返回出站允许透传的 metadata key（已排序），供其它透传组件复用同一份白名单。
+func OutgoingMetadataAllowlist() []string {
+	keys := make([]string, 0, len(outgoingAuthorityMetadataAllowlist))
+	for key := range outgoingAuthorityMetadataAllowlist {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // PrepareOutgoingAuthorityMetadata 清理出站 metadata，并写入当前这一跳 service authority。
@@ -37,6 +51,10 @@ var outgoingAuthorityMetadataAllowlist = map[string]struct{}{
 func PrepareOutgoingAuthorityMetadata(ctx context.Context, md metadata.MD, provider ServiceAuthorityProvider) (metadata.MD, error) {
 	// 先按业务服务出站白名单重建 metadata，清理上一跳普通上下文字段和未知 header。
 	md = filterOutgoingAuthorityMetadata(md)
+	// 镜像流量标记需要贯穿整条调用链，但只按进程内可信标记写入，入站 x-firefly-shadow 不会被直接透传。
+	if service.IsShadow(ctx) {
+		md.Set(constant.Shadow, "1")
+	}
 
 	// 未配置 provider 时只做清理，仅适合获取 service token 的启动链路、authz 这类无下游热路径组件或测试链路。
 	if provider == nil {
@@ -3,9 +3,11 @@ package authz
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
 
 	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -27,6 +29,8 @@ func TestPrepareOutgoingAuthorityMetadata_PreservesUserAuthorityAndOverridesServ
 		constant.TraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
 		constant.TraceState, "vendor=value",
 		constant.Baggage, "tenant=demo",
+		constant.ConsistencyToken, "lsn-42",
+		constant.Shadow, "1",
 		"x-custom-metadata", "should-drop",
 	)
 
@@ -80,6 +84,12 @@ func TestPrepareOutgoingAuthorityMetadata_PreservesUserAuthorityAndOverridesServ
 	if got := prepared.Get(constant.Baggage); len(got) == 0 {
 		t.Fatalf("expected baggage to be preserved")
 	}
+	if got := prepared.Get(constant.Shadow); len(got) != 0 {
+		t.Fatalf("expected untrusted shadow flag to be removed, got %v", got)
+	}
+	if got := prepared.Get(constant.ConsistencyToken); len(got) == 0 || got[0] != "lsn-42" {
+		t.Fatalf("expected consistency token to be preserved, got %v", got)
+	}
 	if got := prepared.Get("x-custom-metadata"); len(got) != 0 {
 		t.Fatalf("expected custom metadata to be removed, got %v", got)
 	}
@@ -99,6 +109,16 @@ func TestPrepareOutgoingAuthorityMetadata_RemovesInheritedServiceAuthorityWhenPr
 	}
 }
 
+func TestPrepareOutgoingAuthorityMetadata_WritesShadowOnlyForTrustedShadowContext(t *testing.T) {
+	prepared, err := PrepareOutgoingAuthorityMetadata(service.WithShadow(context.Background()), metadata.MD{}, nil)
+	if err != nil {
+		t.Fatalf("prepare metadata failed: %v", err)
+	}
+	if got := prepared.Get(constant.Shadow); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("expected trusted shadow flag to be written, got %v", got)
+	}
+}
+
 func TestNewServiceAuthorityUnaryClientInterceptor_ReturnsProviderError(t *testing.T) {
 	expectedErr := errors.New("fetch failed")
 	interceptor := NewServiceAuthorityUnaryClientInterceptor(errorServiceAuthorityProvider{err: expectedErr})
@@ -132,3 +152,17 @@ type fixedServiceAuthorityProvider string
 func (p fixedServiceAuthorityProvider) ServiceAuthority(context.Context) (string, error) {
 	return string(p), nil
 }
+
+func TestOutgoingMetadataAllowlistIsSortedCopy(t *testing.T) {
+	keys := OutgoingMetadataAllowlist()
+	if len(keys) != len(outgoingAuthorityMetadataAllowlist) {
+		t.Fatalf("unexpected allowlist size: %d", len(keys))
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("expected sorted allowlist: %v", keys)
+	}
+	keys[0] = "mutated"
+	if OutgoingMetadataAllowlist()[0] == "mutated" {
+		t.Fatal("expected allowlist to return a fresh copy")
+	}
+}
@@ -0,0 +1,360 @@
+package authz
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJWKSRefreshInterval 是 JWKS 后台刷新的默认间隔。
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+	// DefaultJWKSTimeout 是单次拉取 JWKS 的默认超时时间。
+	DefaultJWKSTimeout = 5 * time.Second
+	// DefaultJWKSMinRefreshInterval 是 kid 未命中触发刷新的最小间隔，防止伪造 kid 放大请求。
+	DefaultJWKSMinRefreshInterval = 30 * time.Second
+	// jwksMaxBodySize 限制 JWKS 响应体大小，避免异常响应占用过多内存。
+	jwksMaxBodySize = 1 << 20
+)
+
+var (
+	// ErrJWKSEndpointsMissing 表示没有配置任何 JWKS 地址。
+	ErrJWKSEndpointsMissing = errors.New("authz jwks endpoints are missing")
+	// ErrJWKSNoUsableKeys 表示 JWKS 中没有可用的 Ed25519 公钥。
+	ErrJWKSNoUsableKeys = errors.New("authz jwks has no usable ed25519 keys")
+)
+
+// JWKSConfig 描述业务服务如何从 JWKS 地址动态获取验签公钥。
+type JWKSConfig struct {
+	// Endpoints 是 JWKS 地址列表，按顺序尝试，第一个成功的结果生效。
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`
+	// RefreshInterval 是后台刷新间隔，例如 5m；为空使用默认值。
+	RefreshInterval string `json:"refresh_interval" yaml:"refresh_interval"`
+	// Timeout 是单次拉取超时时间，例如 5s；为空使用默认值。
+	Timeout string `json:"timeout" yaml:"timeout"`
+	// MaxStale 是刷新持续失败时旧公钥最多继续使用多久，例如 24h；为空表示一直使用直到刷新成功。
+	MaxStale string `json:"max_stale" yaml:"max_stale"`
+}
+
+// JWKSClientOptions 定义 JWKS 客户端依赖。
+type JWKSClientOptions struct {
+	// Endpoints 是 JWKS 地址列表，按顺序尝试。
+	Endpoints []string
+	// HTTPClient 是拉取 JWKS 使用的 HTTP 客户端；为空使用 http.DefaultClient。
+	HTTPClient *http.Client
+	// RefreshInterval 是后台刷新间隔。
+	RefreshInterval time.Duration
+	// Timeout 是单次拉取超时时间。
+	Timeout time.Duration
+	// MaxStale 是刷新持续失败时旧公钥最多继续使用多久；0 表示不限制。
+	MaxStale time.Duration
+	// MinRefreshInterval 是 kid 未命中触发后台刷新的最小间隔。
+	MinRefreshInterval time.Duration
+}
+
+// normalize 补齐默认值。
+func (o JWKSClientOptions) normalize() JWKSClientOptions {
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	if o.RefreshInterval <= 0 {
+		o.RefreshInterval = DefaultJWKSRefreshInterval
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultJWKSTimeout
+	}
+	if o.MinRefreshInterval <= 0 {
+		o.MinRefreshInterval = DefaultJWKSMinRefreshInterval
+	}
+	return o
+}
+
+// JWKSClient 在进程内缓存 JWKS 公钥，并在后台定期刷新。
+//
+// 设计说明：
+// - 请求热路径只读本地缓存，不会同步发起网络调用；
+// - 刷新失败时继续使用上一次成功的公钥（stale-if-error），直到超过 MaxStale；
+// - kid 未命中时异步触发一次刷新，并按 MinRefreshInterval 限流，兼顾密钥轮换与防放大。
+type JWKSClient struct {
+	options JWKSClientOptions
+
+	// mu 保护 keys、fetchedAt 与 lastRefreshErr。
+	mu sync.RWMutex
+	// keys 保存按 kid 索引的公钥。
+	keys map[string]ed25519.PublicKey
+	// fetchedAt 保存最近一次刷新成功的时间。
+	fetchedAt time.Time
+	// lastRefreshErr 保存最近一次刷新错误。
+	lastRefreshErr error
+
+	// refreshMu 保证同一时间只有一个刷新在执行，并保护 lastAttempt。
+	refreshMu sync.Mutex
+	// lastAttempt 保存最近一次尝试刷新的时间，用于 kid 未命中刷新限流。
+	lastAttempt time.Time
+	// refreshing 表示是否已有异步刷新在进行。
+	refreshing bool
+
+	// lifecycleMu 保护后台协程生命周期。
+	lifecycleMu sync.Mutex
+	// lifecycleCancel 保存后台刷新协程的取消函数。
+	lifecycleCancel context.CancelFunc
+}
+
+// NewJWKSClient 根据配置构造 JWKS 客户端，调用方需要在启动期调用 Start。
+func NewJWKSClient(cfg *JWKSConfig) (*JWKSClient, error) {
+	if cfg == nil {
+		return nil, ErrJWKSEndpointsMissing
+	}
+	refreshInterval, err := parseOptionalDuration("refresh_interval", cfg.RefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parseOptionalDuration("timeout", cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	maxStale, err := parseOptionalDuration("max_stale", cfg.MaxStale)
+	if err != nil {
+		return nil, err
+	}
+	return NewJWKSClientWithOptions(JWKSClientOptions{
+		Endpoints:       cfg.Endpoints,
+		RefreshInterval: refreshInterval,
+		Timeout:         timeout,
+		MaxStale:        maxStale,
+	})
+}
+
+// NewJWKSClientWithOptions 使用显式依赖构造 JWKS 客户端。
+func NewJWKSClientWithOptions(options JWKSClientOptions) (*JWKSClient, error) {
+	endpoints := cloneStrings(options.Endpoints)
+	if len(endpoints) == 0 {
+		return nil, ErrJWKSEndpointsMissing
+	}
+	options.Endpoints = endpoints
+	return &JWKSClient{options: options.normalize()}, nil
+}
+
+// PublicKey 按 kid 返回公钥，实现 service.PublicKeyResolver。
+func (c *JWKSClient) PublicKey(kid string) (ed25519.PublicKey, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	fetchedAt := c.fetchedAt
+	c.mu.RUnlock()
+
+	// 超过 MaxStale 的旧公钥不再可信，拒绝使用并尽快刷新。
+	if ok && c.options.MaxStale > 0 && time.Since(fetchedAt) > c.options.MaxStale {
+		ok = false
+	}
+	if !ok {
+		// 未命中可能是 IdP 刚轮换了密钥，异步刷新，不阻塞当前请求。
+		c.triggerRefresh()
+		return nil, false
+	}
+	return key, true
+}
+
+// Refresh 同步拉取一次 JWKS，成功后替换缓存；启动期可调用它保证首批请求可验签。
+func (c *JWKSClient) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	c.lastAttempt = time.Now()
+	c.refreshMu.Unlock()
+
+	keys, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		// 只记录错误，不清理旧公钥，避免 IdP 短暂抖动扩大为验签不可用。
+		c.lastRefreshErr = err
+		return err
+	}
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	c.lastRefreshErr = nil
+	return nil
+}
+
+// LastRefreshError 返回最近一次刷新错误，便于健康检查暴露 JWKS 状态。
+func (c *JWKSClient) LastRefreshError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastRefreshErr
+}
+
+// Start 启动后台刷新协程，并立即异步拉取一次 JWKS。
+func (c *JWKSClient) Start(ctx context.Context) error {
+	if c == nil {
+		return ErrJWKSEndpointsMissing
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+	// 已经启动时直接返回，避免重复创建刷新协程。
+	if c.lifecycleCancel != nil {
+		return nil
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	c.lifecycleCancel = cancel
+	go c.runRefreshLoop(runCtx)
+	return nil
+}
+
+// Stop 停止后台刷新协程。
+func (c *JWKSClient) Stop() {
+	if c == nil {
+		return
+	}
+	c.lifecycleMu.Lock()
+	cancel := c.lifecycleCancel
+	c.lifecycleCancel = nil
+	c.lifecycleMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// runRefreshLoop 立即刷新一次，之后按固定间隔刷新。
+func (c *JWKSClient) runRefreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.options.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		_ = c.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// triggerRefresh 异步触发一次刷新，按 MinRefreshInterval 限流且同一时间只允许一个。
+func (c *JWKSClient) triggerRefresh() {
+	c.refreshMu.Lock()
+	if c.refreshing || time.Since(c.lastAttempt) < c.options.MinRefreshInterval {
+		c.refreshMu.Unlock()
+		return
+	}
+	c.refreshing = true
+	c.refreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			c.refreshMu.Lock()
+			c.refreshing = false
+			c.refreshMu.Unlock()
+		}()
+		_ = c.Refresh(context.Background())
+	}()
+}
+
+// fetch 按顺序尝试各个地址，返回第一个成功解析的公钥集合。
+func (c *JWKSClient) fetch(ctx context.Context) (map[string]ed25519.PublicKey, error) {
+	var errs error
+	for _, endpoint := range c.options.Endpoints {
+		keys, err := c.fetchEndpoint(ctx, endpoint)
+		if err == nil {
+			return keys, nil
+		}
+		errs = errors.Join(errs, err)
+	}
+	return nil, errs
+}
+
+// fetchEndpoint 拉取并解析单个 JWKS 地址。
+func (c *JWKSClient) fetchEndpoint(ctx context.Context, endpoint string) (map[string]ed25519.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch authz jwks %s: %w", endpoint, err)
+	}
+	response, err := c.options.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetch authz jwks %s: %w", endpoint, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch authz jwks %s: unexpected status %d", endpoint, response.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, jwksMaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("fetch authz jwks %s: %w", endpoint, err)
+	}
+	keys, err := ParseJWKS(body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch authz jwks %s: %w", endpoint, err)
+	}
+	return keys, nil
+}
+
+// jsonWebKeySet 是 RFC 7517 JWK Set 中需要的字段。
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jsonWebKey 是 RFC 8037 OKP 公钥中需要的字段。
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	X   string `json:"x"`
+}
+
+// ParseJWKS 解析 JWK Set，只保留 kty=OKP、crv=Ed25519 的签名公钥。
+func ParseJWKS(data []byte) (map[string]ed25519.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		// 只接受 Ed25519 签名公钥，其他算法和加密用途的 key 直接跳过。
+		if key.Kty != "OKP" || key.Crv != "Ed25519" || key.Kid == "" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.X, "="))
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			continue
+		}
+		keys[key.Kid] = ed25519.PublicKey(raw)
+	}
+	if len(keys) == 0 {
+		return nil, ErrJWKSNoUsableKeys
+	}
+	return keys, nil
+}
+
+// parseOptionalDuration 解析可选 duration 配置，为空时返回 0。
+func parseOptionalDuration(name string, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse authz jwks %s: %w", name, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("parse authz jwks %s: value must be non-negative", name)
+	}
+	return duration, nil
+}
@@ -0,0 +1,167 @@
+package authz
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jwksTestServer 模拟可切换密钥和故障状态的 JWKS 地址。
+type jwksTestServer struct {
+	mu     sync.Mutex
+	keys   map[string]ed25519.PublicKey
+	failed bool
+}
+
+func (s *jwksTestServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 故障状态下模拟 IdP 不可用。
+	if s.failed {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	set := map[string]any{"keys": []map[string]string{}}
+	keys := make([]map[string]string, 0, len(s.keys))
+	for kid, key := range s.keys {
+		keys = append(keys, map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"kid": kid,
+			"use": "sig",
+			"x":   base64.RawURLEncoding.EncodeToString(key),
+		})
+	}
+	set["keys"] = keys
+	_ = json.NewEncoder(writer).Encode(set)
+}
+
+func (s *jwksTestServer) set(keys map[string]ed25519.PublicKey, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.failed = failed
+}
+
+func generateTestPublicKey(t *testing.T) ed25519.PublicKey {
+	t.Helper()
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key failed: %v", err)
+	}
+	return publicKey
+}
+
+func TestJWKSClientServesStaleKeysOnError(t *testing.T) {
+	key := generateTestPublicKey(t)
+	backend := &jwksTestServer{}
+	backend.set(map[string]ed25519.PublicKey{"k1": key}, false)
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client, err := NewJWKSClientWithOptions(JWKSClientOptions{Endpoints: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("new jwks client failed: %v", err)
+	}
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if got, ok := client.PublicKey("k1"); !ok || !got.Equal(key) {
+		t.Fatalf("expected k1 to be cached")
+	}
+
+	// IdP 故障时刷新失败，但旧公钥继续可用。
+	backend.set(nil, true)
+	if err := client.Refresh(context.Background()); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+	if client.LastRefreshError() == nil {
+		t.Fatalf("expected last refresh error to be recorded")
+	}
+	if _, ok := client.PublicKey("k1"); !ok {
+		t.Fatalf("expected stale key to be served on refresh error")
+	}
+}
+
+func TestJWKSClientDropsKeysAfterMaxStale(t *testing.T) {
+	backend := &jwksTestServer{}
+	backend.set(map[string]ed25519.PublicKey{"k1": generateTestPublicKey(t)}, false)
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client, _ := NewJWKSClientWithOptions(JWKSClientOptions{
+		Endpoints:          []string{server.URL},
+		MaxStale:           20 * time.Millisecond,
+		MinRefreshInterval: time.Hour,
+	})
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := client.PublicKey("k1"); ok {
+		t.Fatalf("expected key older than max stale to be rejected")
+	}
+}
+
+func TestJWKSClientRefreshesOnUnknownKid(t *testing.T) {
+	backend := &jwksTestServer{}
+	backend.set(map[string]ed25519.PublicKey{"k1": generateTestPublicKey(t)}, false)
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client, _ := NewJWKSClientWithOptions(JWKSClientOptions{
+		Endpoints:          []string{"http://127.0.0.1:1/unreachable", server.URL},
+		MinRefreshInterval: time.Millisecond,
+	})
+	if err := client.Refresh(context.Background()); err != nil {
+		t.Fatalf("expected fallback endpoint to succeed: %v", err)
+	}
+
+	// IdP 轮换密钥后，未命中的 kid 会异步触发刷新。
+	rotated := generateTestPublicKey(t)
+	backend.set(map[string]ed25519.PublicKey{"k2": rotated}, false)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := client.PublicKey("k2"); ok {
+		t.Fatalf("expected first lookup of unknown kid to miss")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got, ok := client.PublicKey("k2"); ok && got.Equal(rotated) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected rotated key to be fetched in background")
+}
+
+func TestParseJWKSSkipsUnsupportedKeys(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[{"kty":"RSA","kid":"r1","n":"x","e":"AQAB"}]}`))
+	if !errors.Is(err, ErrJWKSNoUsableKeys) {
+		t.Fatalf("expected ErrJWKSNoUsableKeys, got %v", err)
+	}
+	if _, err := NewJWKSClientWithOptions(JWKSClientOptions{}); !errors.Is(err, ErrJWKSEndpointsMissing) {
+		t.Fatalf("expected ErrJWKSEndpointsMissing, got %v", err)
+	}
+}
+
+func TestNewVerificationOptionsWithJWKSOnly(t *testing.T) {
+	options, err := NewVerificationOptions(&VerificationConfig{
+		JWKS: &JWKSConfig{Endpoints: []string{"https://idp.example.com/.well-known/jwks.json"}},
+	})
+	if err != nil {
+		t.Fatalf("build verification options failed: %v", err)
+	}
+	if options.JWKS == nil || options.AuthzVerification.KeyResolver == nil {
+		t.Fatalf("expected jwks client to be wired as key resolver")
+	}
+}
@@ -33,6 +33,8 @@ type VerificationConfig struct {
 	ClockSkew string `json:"clock_skew" yaml:"clock_skew"`
 	// SkipMethods 是不执行 authz 上下文验签的 gRPC FullMethod 列表。
 	SkipMethods []string `json:"skip_methods" yaml:"skip_methods"`
+	// JWKS 非空时从 JWKS 地址动态获取按 kid 索引的公钥，可与 PublicKeyPath 同时配置作为兜底。
+	JWKS *JWKSConfig `json:"jwks" yaml:"jwks"`
 }
 
 // VerificationOptions 是业务服务接入 go-micro gRPC middleware 时需要的验签配置。
@@ -41,6 +43,8 @@ type VerificationOptions struct {
 	AuthzVerification *service.AuthzSignVerificationOptions
 	// AuthzSkipMethods 是传给 ServiceContextUnaryInterceptor 的跳过验签方法列表。
 	AuthzSkipMethods []string
+	// JWKS 是配置了 jwks 时构造的公钥缓存，调用方需要在启动期调用 Start 开启后台刷新。
+	JWKS *JWKSClient
 }
 
 // NewVerificationOptions 根据业务服务配置构造 go-micro 服务侧验签选项。
@@ -60,36 +64,49 @@ func NewVerificationOptions(cfg *VerificationConfig) (*VerificationOptions, erro
 	if issuer == "" {
 		issuer = DefaultIssuer
 	}
-	// 启用验签后必须显式配置公钥路径。
+	// 启用验签后必须显式配置公钥路径或 JWKS 地址。
 	publicKeyPath := strings.TrimSpace(cfg.PublicKeyPath)
-	if publicKeyPath == "" {
-		return nil, fmt.Errorf("authz verification public_key_path is required")
+	if publicKeyPath == "" && cfg.JWKS == nil {
+		return nil, fmt.Errorf("authz verification public_key_path or jwks.endpoints is required")
 	}
 
-	// 从 PEM 文件加载 Ed25519 公钥。
-	publicKey, err := LoadEd25519PublicKey(publicKeyPath)
-	if err != nil {
-		return nil, err
-	}
 	// 解析允许的时钟偏差。
 	clockSkew, err := parseClockSkew(cfg.ClockSkew)
 	if err != nil {
 		return nil, err
 	}
-
-	// PublicKeys 使用 kid 索引，虽然当前只有 default，但保持与 service 验签模型一致。
 	verification := &service.AuthzSignVerificationOptions{
-		PublicKeys: map[string]ed25519.PublicKey{
-			kid: publicKey,
-		},
 		Issuer:    issuer,
 		ClockSkew: clockSkew,
 	}
 
+	// 配置了公钥路径时从 PEM 文件加载 Ed25519 公钥。
+	if publicKeyPath != "" {
+		publicKey, err := LoadEd25519PublicKey(publicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		// PublicKeys 使用 kid 索引，虽然当前只有 default，但保持与 service 验签模型一致。
+		verification.PublicKeys = map[string]ed25519.PublicKey{
+			kid: publicKey,
+		}
+	}
+
+	// 配置了 JWKS 时优先按 kid 动态查找公钥，本地 PEM 公钥作为兜底。
+	var jwks *JWKSClient
+	if cfg.JWKS != nil {
+		jwks, err = NewJWKSClient(cfg.JWKS)
+		if err != nil {
+			return nil, err
+		}
+		verification.KeyResolver = jwks
+	}
+
 	// 返回 middleware 可直接使用的 options。
 	return &VerificationOptions{
 		AuthzVerification: verification,
 		AuthzSkipMethods:  cloneStrings(cfg.SkipMethods),
+		JWKS:              jwks,
 	}, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"encoding/pem"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -94,10 +95,10 @@ func TestNewVerificationOptionsUsesDefaults(t *testing.T) {
 }
 
 func TestNewVerificationOptionsRequiresPublicKeyPath(t *testing.T) {
-	// 配置了验签结构后 public_key_path 必填。
+	// 配置了验签结构后 public_key_path 与 jwks 至少配置一个。
 	_, err := NewVerificationOptions(&VerificationConfig{})
-	if err == nil {
-		t.Fatalf("expected error when public key path is empty")
+	if err == nil || !strings.Contains(err.Error(), "public_key_path or jwks.endpoints is required") {
+		t.Fatalf("expected public_key_path or jwks.endpoints required error, got %v", err)
 	}
 }
 
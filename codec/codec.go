@@ -0,0 +1,27 @@
+// Package codec 提供可插拔的 gRPC 序列化编解码器，以及编解码使用量指标。
+package codec
+
+import (
+	"google.golang.org/grpc/encoding"
+)
+
+// RegisterJSON 把 JSON 编解码器注册到 gRPC 全局编解码表。
+//
+// 注册后：
+// - 服务端会按请求的 content-subtype（application/grpc+json）自动选用 JSON 编解码；
+// - 客户端通过 grpc.CallContentSubtype(JSONName) 或 invocation.UnaryInvoker.WithContentSubtype 选用 JSON。
+//
+// 内部服务间调用不设置 content-subtype 时仍保持默认 protobuf。
+func RegisterJSON() {
+	Register(JSONCodec{})
+}
+
+// Register 把任意编解码器包装上使用量指标后注册到 gRPC 全局编解码表。
+//
+// gRPC 的编解码表不是并发安全的，只应在进程启动阶段（init 或 main 早期）调用。
+func Register(codec encoding.Codec) {
+	if codec == nil {
+		return
+	}
+	encoding.RegisterCodec(WithMetrics(codec))
+}
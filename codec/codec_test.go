@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestJSONCodecRoundTripsProtoMessage(t *testing.T) {
+	codec := JSONCodec{}
+
+	data, err := codec.Marshal(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if string(data) != `"hello"` {
+		t.Fatalf("unexpected json payload: %s", data)
+	}
+
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if decoded.GetValue() != "hello" {
+		t.Fatalf("unexpected decoded value: %q", decoded.GetValue())
+	}
+}
+
+func TestJSONCodecFallsBackToEncodingJSON(t *testing.T) {
+	codec := JSONCodec{}
+
+	data, err := codec.Marshal(map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	decoded := map[string]string{}
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if decoded["k"] != "v" {
+		t.Fatalf("unexpected decoded value: %v", decoded)
+	}
+}
+
+func TestRegisterJSON(t *testing.T) {
+	RegisterJSON()
+
+	registered := encoding.GetCodec(JSONName)
+	if registered == nil {
+		t.Fatal("expected json codec to be registered")
+	}
+	if _, ok := registered.(*metricsCodec); !ok {
+		t.Fatalf("expected registered codec to record metrics, got %T", registered)
+	}
+}
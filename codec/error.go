@@ -0,0 +1,18 @@
+package codec
+
+import "errors"
+
+var (
+	// ErrBlobStoreIsNil 表示启用大报文卸载时没有提供对象存储实现。
+	ErrBlobStoreIsNil = errors.New("codec blob store is nil")
+	// ErrOffloadSecretIsEmpty 表示启用大报文卸载时没有提供信封签名密钥。
+	ErrOffloadSecretIsEmpty = errors.New("codec offload secret is empty")
+	// ErrOffloadSignatureInvalid 表示收到的卸载引用信封签名校验失败。
+	ErrOffloadSignatureInvalid = errors.New("codec offload envelope signature is invalid")
+	// ErrOffloadPayloadTooLarge 表示卸载报文超过允许还原的最大字节数。
+	ErrOffloadPayloadTooLarge = errors.New("codec offload payload is too large")
+	// ErrOffloadEnvelopeInvalid 表示收到的卸载引用信封无法解析。
+	ErrOffloadEnvelopeInvalid = errors.New("codec offload envelope is invalid")
+	// ErrOffloadChecksumMismatch 表示从对象存储取回的报文与信封记录的摘要不一致。
+	ErrOffloadChecksumMismatch = errors.New("codec offload checksum mismatch")
+)
@@ -0,0 +1,39 @@
+package codec
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONName 是 JSON 编解码器注册到 gRPC 时使用的 content-subtype。
+const JSONName = "json"
+
+// JSONCodec 是面向低算力客户端的 JSON 编解码器。
+//
+// proto 消息使用 protojson 保持字段命名与 proto 定义一致；
+// 非 proto 值回退到标准库 encoding/json。
+type JSONCodec struct{}
+
+// Marshal 把消息序列化为 JSON。
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	if message, ok := v.(proto.Message); ok {
+		return protojson.Marshal(message)
+	}
+	return json.Marshal(v)
+}
+
+// Unmarshal 把 JSON 反序列化到消息。
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	if message, ok := v.(proto.Message); ok {
+		// 忽略未知字段，避免客户端比服务端 proto 新时直接失败。
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, message)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Name 返回 gRPC content-subtype。
+func (JSONCodec) Name() string {
+	return JSONName
+}
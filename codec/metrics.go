@@ -0,0 +1,83 @@
+package codec
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/encoding"
+)
+
+// pkgName 作为 otel instrumentation scope name 使用。
+const pkgName = "github.com/fireflycore/go-micro/codec"
+
+const (
+	// operationMarshal 表示序列化方向。
+	operationMarshal = "marshal"
+	// operationUnmarshal 表示反序列化方向。
+	operationUnmarshal = "unmarshal"
+)
+
+// metricsCodec 在原始编解码器外层记录调用次数与报文字节数。
+type metricsCodec struct {
+	encoding.Codec
+	// messages 记录编解码次数，按 codec/operation/result 维度聚合。
+	messages metric.Int64Counter
+	// bytes 记录编解码报文字节数，按 codec/operation 维度聚合。
+	bytes metric.Int64Counter
+}
+
+// WithMetrics 返回记录使用量指标的编解码器包装。
+//
+// 指标通过全局 MeterProvider 创建，telemetry.NewProviders 启用 Metrics 后即可在 /metrics 中看到：
+// - rpc.codec.messages：编解码次数
+// - rpc.codec.bytes：编解码报文字节数
+func WithMetrics(codec encoding.Codec) encoding.Codec {
+	meter := otel.Meter(pkgName)
+	// 指标创建失败时返回 noop 计数器，不影响编解码本身。
+	messages, _ := meter.Int64Counter("rpc.codec.messages",
+		metric.WithDescription("Number of messages encoded or decoded by gRPC codec."),
+	)
+	bytes, _ := meter.Int64Counter("rpc.codec.bytes",
+		metric.WithDescription("Number of bytes encoded or decoded by gRPC codec."),
+		metric.WithUnit("By"),
+	)
+	return &metricsCodec{
+		Codec:    codec,
+		messages: messages,
+		bytes:    bytes,
+	}
+}
+
+// Marshal 序列化消息并记录指标。
+func (c *metricsCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.Codec.Marshal(v)
+	c.record(operationMarshal, len(data), err)
+	return data, err
+}
+
+// Unmarshal 反序列化消息并记录指标。
+func (c *metricsCodec) Unmarshal(data []byte, v any) error {
+	err := c.Codec.Unmarshal(data, v)
+	c.record(operationUnmarshal, len(data), err)
+	return err
+}
+
+// record 统一写入一次编解码的指标。
+func (c *metricsCodec) record(operation string, size int, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	ctx := context.Background()
+	c.messages.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("codec", c.Name()),
+		attribute.String("operation", operation),
+		attribute.String("result", result),
+	))
+	c.bytes.Add(ctx, int64(size), metric.WithAttributes(
+		attribute.String("codec", c.Name()),
+		attribute.String("operation", operation),
+	))
+}
@@ -0,0 +1,202 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// DefaultOffloadThreshold 是触发大报文卸载的默认字节数阈值。
+	//
+	// 取值低于 gRPC 默认 4MB 接收上限，保证信封替换后的报文始终能被对端接收。
+	DefaultOffloadThreshold = 1 << 20
+	// DefaultOffloadTimeout 是单次上传或下载对象的默认超时时间。
+	DefaultOffloadTimeout = 30 * time.Second
+	// DefaultOffloadMaxSize 是接收方允许还原的默认最大报文字节数。
+	DefaultOffloadMaxSize = 64 << 20
+)
+
+// offloadMagic 是卸载引用信封的固定前缀。
+//
+// protobuf 的字段号不能为 0，合法 protobuf 报文首字节不可能是 0x00；
+// JSON 报文同样不会以 0x00 开头，因此该前缀不会与正常报文冲突。
+var offloadMagic = []byte("\x00ffblob:")
+
+// BlobStore 定义大报文卸载使用的对象存储抽象。
+//
+// 业务方可以基于 S3、OSS、MinIO 等实现该接口；收发双方必须能访问同一个存储。
+type BlobStore interface {
+	// Put 上传报文并返回可被对端 Get 的引用。
+	Put(ctx context.Context, data []byte) (string, error)
+	// Open 根据引用打开原始报文；调用方只读取信封声明的字节数，读取完成后负责 Close。
+	Open(ctx context.Context, ref string) (io.ReadCloser, error)
+}
+
+// OffloadOptions 定义大报文卸载的配置。
+type OffloadOptions struct {
+	// Store 表示存放大报文的对象存储，必填。
+	Store BlobStore
+	// Threshold 表示超过多少字节时卸载；未设置时使用 DefaultOffloadThreshold。
+	Threshold int
+	// Timeout 表示单次上传或下载的超时时间；未设置时使用 DefaultOffloadTimeout。
+	Timeout time.Duration
+	// Secret 表示收发双方共享的签名密钥，必填。
+	//
+	// 信封中的引用、大小与摘要由发送方用该密钥签名，接收方验签通过后才会访问对象存储，
+	// 避免调用方伪造引用让服务端读取存储中的任意对象。
+	Secret []byte
+	// MaxSize 表示接收方允许还原的最大报文字节数；未设置时使用 DefaultOffloadMaxSize。
+	//
+	// 卸载后的报文绕过了 gRPC MaxRecvMsgSize 与报文大小拦截器，应与服务允许的最大请求体保持一致。
+	MaxSize int
+}
+
+// offloadEnvelope 是替换原始报文的引用信封。
+type offloadEnvelope struct {
+	// Ref 表示对象存储中的引用。
+	Ref string `json:"ref"`
+	// Size 表示原始报文字节数。
+	Size int `json:"size"`
+	// Sha256 表示原始报文摘要，用于下载后校验完整性。
+	Sha256 string `json:"sha256"`
+	// Mac 表示发送方对 Ref、Size、Sha256 的 HMAC-SHA256 签名。
+	Mac string `json:"mac"`
+}
+
+// sign 计算信封签名。
+func (e offloadEnvelope) sign(secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(e.Ref))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.Itoa(e.Size)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(e.Sha256))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// offloadCodec 在原始编解码器外层透明完成大报文上传与还原。
+type offloadCodec struct {
+	encoding.Codec
+	// options 保存归一化后的卸载配置。
+	options OffloadOptions
+}
+
+// WithOffload 返回支持大报文卸载的编解码器包装。
+//
+// 发送方序列化后的报文超过阈值时，会上传到对象存储并以引用信封替换；
+// 接收方识别到信封后下载原始报文再交给内层编解码器，业务 handler 无感知。
+// 收发双方都必须注册同一个包装并使用相同的 Secret，否则对端无法识别或验证信封。
+func WithOffload(codec encoding.Codec, options OffloadOptions) (encoding.Codec, error) {
+	if options.Store == nil {
+		return nil, ErrBlobStoreIsNil
+	}
+	if len(options.Secret) == 0 {
+		return nil, ErrOffloadSecretIsEmpty
+	}
+	if options.Threshold <= 0 {
+		options.Threshold = DefaultOffloadThreshold
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultOffloadTimeout
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultOffloadMaxSize
+	}
+	return &offloadCodec{
+		Codec:   codec,
+		options: options,
+	}, nil
+}
+
+// RegisterOffload 把支持大报文卸载的 protobuf 编解码器注册为默认 proto。
+func RegisterOffload(options OffloadOptions) error {
+	codec, err := WithOffload(ProtoCodec{}, options)
+	if err != nil {
+		return err
+	}
+	Register(codec)
+	return nil
+}
+
+// Marshal 序列化消息，超过阈值时上传并返回引用信封。
+func (c *offloadCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.Codec.Marshal(v)
+	if err != nil || len(data) <= c.options.Threshold {
+		return data, err
+	}
+	// 超过对端还原上限的报文上传后也会被拒绝，这里提前失败。
+	if len(data) > c.options.MaxSize {
+		return nil, ErrOffloadPayloadTooLarge
+	}
+
+	// gRPC 编解码接口不携带 ctx，这里使用独立超时控制上传耗时。
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.Timeout)
+	defer cancel()
+
+	ref, err := c.options.Store.Put(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("codec: offload payload: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	envelope := offloadEnvelope{
+		Ref:    ref,
+		Size:   len(data),
+		Sha256: hex.EncodeToString(sum[:]),
+	}
+	envelope.Mac = envelope.sign(c.options.Secret)
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(make([]byte, 0, len(offloadMagic)+len(encoded)), offloadMagic...), encoded...), nil
+}
+
+// Unmarshal 识别引用信封并还原原始报文后再反序列化。
+//
+// 信封必须先通过签名与大小校验才会访问对象存储，下载时最多读取信封声明的字节数。
+func (c *offloadCodec) Unmarshal(data []byte, v any) error {
+	if !bytes.HasPrefix(data, offloadMagic) {
+		return c.Codec.Unmarshal(data, v)
+	}
+
+	var envelope offloadEnvelope
+	if err := json.Unmarshal(data[len(offloadMagic):], &envelope); err != nil || envelope.Ref == "" || envelope.Size < 0 {
+		return ErrOffloadEnvelopeInvalid
+	}
+	if !hmac.Equal([]byte(envelope.sign(c.options.Secret)), []byte(envelope.Mac)) {
+		return ErrOffloadSignatureInvalid
+	}
+	if envelope.Size > c.options.MaxSize {
+		return ErrOffloadPayloadTooLarge
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.Timeout)
+	defer cancel()
+
+	reader, err := c.options.Store.Open(ctx, envelope.Ref)
+	if err != nil {
+		return fmt.Errorf("codec: restore offloaded payload: %w", err)
+	}
+	defer reader.Close()
+	// 多读一个字节用于识别对象比信封声明更大的情况，内存占用不超过 Size+1。
+	payload, err := io.ReadAll(io.LimitReader(reader, int64(envelope.Size)+1))
+	if err != nil {
+		return fmt.Errorf("codec: restore offloaded payload: %w", err)
+	}
+	// 下载结果必须与信封记录一致，避免对象被覆盖或截断后静默解析。
+	sum := sha256.Sum256(payload)
+	if len(payload) != envelope.Size || hex.EncodeToString(sum[:]) != envelope.Sha256 {
+		return ErrOffloadChecksumMismatch
+	}
+	return c.Codec.Unmarshal(payload, v)
+}
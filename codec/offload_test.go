@@ -0,0 +1,190 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var testOffloadSecret = []byte("offload-test-secret")
+
+type memoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	opens int
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: map[string][]byte{}}
+}
+
+func (s *memoryBlobStore) Put(_ context.Context, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref := "blob-" + strconv.Itoa(len(s.blobs))
+	s.blobs[ref] = append([]byte(nil), data...)
+	return ref, nil
+}
+
+func (s *memoryBlobStore) Open(_ context.Context, ref string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opens++
+	data, ok := s.blobs[ref]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestOffloadCodecKeepsSmallPayloadInline(t *testing.T) {
+	store := newMemoryBlobStore()
+	codec, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := codec.Marshal(wrapperspb.String("small"))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if len(store.blobs) != 0 {
+		t.Fatalf("expected small payload to stay inline, got %d blobs", len(store.blobs))
+	}
+
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); err != nil || decoded.GetValue() != "small" {
+		t.Fatalf("unexpected decode result: %q, %v", decoded.GetValue(), err)
+	}
+}
+
+func TestOffloadCodecRoundTripsLargePayload(t *testing.T) {
+	store := newMemoryBlobStore()
+	codec, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	large := strings.Repeat("x", 1024)
+	data, err := codec.Marshal(wrapperspb.String(large))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if len(store.blobs) != 1 || len(data) >= 1024 {
+		t.Fatalf("expected payload to be offloaded, blobs=%d size=%d", len(store.blobs), len(data))
+	}
+
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if decoded.GetValue() != large {
+		t.Fatalf("unexpected restored payload length: %d", len(decoded.GetValue()))
+	}
+}
+
+func TestOffloadCodecRejectsTamperedBlob(t *testing.T) {
+	store := newMemoryBlobStore()
+	codec, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := codec.Marshal(wrapperspb.String(strings.Repeat("x", 1024)))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	store.blobs["blob-0"] = []byte("tampered")
+
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); !errors.Is(err, ErrOffloadChecksumMismatch) {
+		t.Fatalf("expected ErrOffloadChecksumMismatch, got %v", err)
+	}
+}
+
+func TestWithOffloadRequiresStore(t *testing.T) {
+	if _, err := WithOffload(ProtoCodec{}, OffloadOptions{}); !errors.Is(err, ErrBlobStoreIsNil) {
+		t.Fatalf("expected ErrBlobStoreIsNil, got %v", err)
+	}
+}
+
+func TestWithOffloadRequiresSecret(t *testing.T) {
+	if _, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: newMemoryBlobStore()}); !errors.Is(err, ErrOffloadSecretIsEmpty) {
+		t.Fatalf("expected ErrOffloadSecretIsEmpty, got %v", err)
+	}
+}
+
+func TestOffloadCodecRejectsForgedEnvelopeBeforeOpen(t *testing.T) {
+	store := newMemoryBlobStore()
+	store.blobs["other-tenant/secret"] = []byte("private")
+	codec, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 调用方自行构造引用与摘要，但无法生成有效签名。
+	forged, _ := json.Marshal(offloadEnvelope{Ref: "other-tenant/secret", Size: 7, Sha256: "x", Mac: "y"})
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(append(append([]byte(nil), offloadMagic...), forged...), &decoded); !errors.Is(err, ErrOffloadSignatureInvalid) {
+		t.Fatalf("expected ErrOffloadSignatureInvalid, got %v", err)
+	}
+	if store.opens != 0 {
+		t.Fatalf("expected forged envelope to be rejected before reaching the store, opens=%d", store.opens)
+	}
+}
+
+func TestOffloadCodecEnforcesMaxSize(t *testing.T) {
+	store := newMemoryBlobStore()
+	sender, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receiver, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret, MaxSize: 512})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := sender.Marshal(wrapperspb.String(strings.Repeat("x", 1024)))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	var decoded wrapperspb.StringValue
+	if err := receiver.Unmarshal(data, &decoded); !errors.Is(err, ErrOffloadPayloadTooLarge) {
+		t.Fatalf("expected ErrOffloadPayloadTooLarge, got %v", err)
+	}
+	if store.opens != 0 {
+		t.Fatalf("expected oversized envelope to be rejected before reaching the store, opens=%d", store.opens)
+	}
+
+	if _, err := receiver.Marshal(wrapperspb.String(strings.Repeat("x", 1024))); !errors.Is(err, ErrOffloadPayloadTooLarge) {
+		t.Fatalf("expected sender to reject payload above MaxSize, got %v", err)
+	}
+}
+
+func TestOffloadCodecBoundsRestoredPayload(t *testing.T) {
+	store := newMemoryBlobStore()
+	codec, err := WithOffload(ProtoCodec{}, OffloadOptions{Store: store, Threshold: 64, Secret: testOffloadSecret})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := codec.Marshal(wrapperspb.String(strings.Repeat("x", 1024)))
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	// 对象被替换为远大于信封声明的内容时，只读取 Size+1 字节即判定不一致。
+	store.blobs["blob-0"] = bytes.Repeat([]byte("y"), 1<<20)
+
+	var decoded wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); !errors.Is(err, ErrOffloadChecksumMismatch) {
+		t.Fatalf("expected ErrOffloadChecksumMismatch, got %v", err)
+	}
+}
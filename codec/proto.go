@@ -0,0 +1,39 @@
+package codec
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoName 是 gRPC 默认 protobuf 编解码器的 content-subtype。
+const ProtoName = "proto"
+
+// ProtoCodec 是与 gRPC 默认行为一致的 protobuf 编解码器。
+//
+// 它主要用于被 WithOffload / WithMetrics 等包装后重新注册为 proto，
+// 从而在不改变 content-subtype 的前提下增强默认编解码行为。
+type ProtoCodec struct{}
+
+// Marshal 把 proto 消息序列化为二进制。
+func (ProtoCodec) Marshal(v any) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("codec: failed to marshal, message is %T, want proto.Message", v)
+	}
+	return proto.Marshal(message)
+}
+
+// Unmarshal 把二进制反序列化到 proto 消息。
+func (ProtoCodec) Unmarshal(data []byte, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("codec: failed to unmarshal, message is %T, want proto.Message", v)
+	}
+	return proto.Unmarshal(data, message)
+}
+
+// Name 返回 gRPC content-subtype。
+func (ProtoCodec) Name() string {
+	return ProtoName
+}
@@ -0,0 +1,9 @@
+// Package consistency 定义读己之写一致性令牌的传播约定。
+package consistency
+
+import "errors"
+
+var (
+	// ErrTokenEmpty 表示一致性令牌为空。
+	ErrTokenEmpty = errors.New("consistency token is empty")
+)
@@ -0,0 +1,51 @@
+package consistency
+
+import (
+	"context"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// SetHeader 在写接口响应 header 中写入一致性令牌，例如提交事务后的数据库 LSN 或逻辑版本号。
+func SetHeader(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrTokenEmpty
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(constant.ConsistencyToken, token))
+}
+
+// FromHeader 从客户端收到的响应 header 中读取一致性令牌；不存在时返回空字符串。
+//
+// 配合 grpc.Header(&header) 调用选项使用。
+func FromHeader(header metadata.MD) string {
+	values := header.Get(constant.ConsistencyToken)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// AppendToOutgoingContext 把一致性令牌写入出站 metadata，供后续读请求携带。
+func AppendToOutgoingContext(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constant.ConsistencyToken, token)
+}
+
+// FromContext 读取当前请求携带的一致性令牌。
+//
+// 优先读取入口中间件注入的 service.Context，未注入时回退读取入站 metadata；不存在时返回空字符串。
+func FromContext(ctx context.Context) string {
+	if value, ok := service.FromContext(ctx); ok && value.ConsistencyToken != "" {
+		return value.ConsistencyToken
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return FromHeader(md)
+}
@@ -0,0 +1,41 @@
+package consistency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestFromContextPrefersServiceContext(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.ConsistencyToken, "lsn-1"))
+	if got := FromContext(ctx); got != "lsn-1" {
+		t.Fatalf("expected incoming metadata fallback, got %q", got)
+	}
+
+	ctx = service.WithContext(ctx, &service.Context{ConsistencyToken: "lsn-2"})
+	if got := FromContext(ctx); got != "lsn-2" {
+		t.Fatalf("expected service context token, got %q", got)
+	}
+
+	if got := FromContext(context.Background()); got != "" {
+		t.Fatalf("expected empty token, got %q", got)
+	}
+}
+
+func TestAppendToOutgoingContext(t *testing.T) {
+	ctx := AppendToOutgoingContext(context.Background(), "lsn-3")
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if got := FromHeader(md); got != "lsn-3" {
+		t.Fatalf("unexpected outgoing token: %q", got)
+	}
+
+	if AppendToOutgoingContext(ctx, "") != ctx {
+		t.Fatal("expected empty token to keep ctx unchanged")
+	}
+	if err := SetHeader(context.Background(), ""); err != ErrTokenEmpty {
+		t.Fatalf("expected ErrTokenEmpty, got %v", err)
+	}
+}
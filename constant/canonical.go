@@ -0,0 +1,113 @@
+package constant
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LegacyHeaderAliases 定义旧 pkg/core 链路使用过的 header key 到 current key 的映射。
+//
+// 它只用于多版本服务混布的迁移期，新代码不应再写入左侧的旧 key。
+var LegacyHeaderAliases = map[string]string{
+	HeaderPrefix + "client-ip":     XRealIp,
+	HeaderPrefix + "app-user-id":   UserId,
+	HeaderPrefix + "app-tenant-id": TenantId,
+	HeaderPrefix + "app-org-ids":   OrgIds,
+	HeaderPrefix + "app-post-ids":  PostIds,
+	HeaderPrefix + "app-role-ids":  RoleIds,
+	HeaderPrefix + "app-session":   Session,
+}
+
+// knownHeaders 是 current 协议定义的全部 x-firefly-* key。
+var knownHeaders = map[string]struct{}{
+	AppLanguage:       {},
+	AppVersion:        {},
+	SubjectType:       {},
+	DecisionId:        {},
+	AuthzSign:         {},
+	SystemType:        {},
+	SystemName:        {},
+	SystemVersion:     {},
+	ClientType:        {},
+	ClientName:        {},
+	ClientVersion:     {},
+	UserAuthority:     {},
+	ServiceAuthority:  {},
+	ServiceAppId:      {},
+	ServiceInstanceId: {},
+	UserId:            {},
+	Session:           {},
+	OrgIds:            {},
+	PostIds:           {},
+	RoleIds:           {},
+	AppId:             {},
+	TenantId:          {},
+	InvokeAppId:       {},
+	TargetAppId:       {},
+	ApiMethod:         {},
+	ApiPath:           {},
+	OperationId:       {},
+	ConsistencyToken:  {},
+	Shadow:            {},
+	RetryAttempt:      {},
+	IdempotencyKey:    {},
+}
+
+// CanonicalizeOptions 定义 header 规范化行为。
+type CanonicalizeOptions struct {
+	// RejectUnknown 表示遇到 current 协议未定义的 x-firefly-* key 时返回 ErrUnknownHeader。
+	RejectUnknown bool
+}
+
+// IsKnownHeader 判断 key 是否为 current 协议定义的 x-firefly-* header。
+func IsKnownHeader(key string) bool {
+	_, ok := knownHeaders[strings.ToLower(strings.TrimSpace(key))]
+	return ok
+}
+
+// Canonicalize 原地规范化 gRPC metadata 风格的 header map。
+//
+// 处理规则：
+// 1. 所有 key 去除首尾空白并转为小写；
+// 2. LegacyHeaderAliases 中的旧 key 映射到 current key；current key 已存在时以 current key 为准，旧值丢弃；
+// 3. RejectUnknown 开启时，未定义的 x-firefly-* key 会导致返回错误，且 md 保持不变。
+//
+// http.Header 依赖 MIME 规范大小写，不应直接传入；HTTP 场景请先转换为小写 key 的 map。
+func Canonicalize(md map[string][]string, options CanonicalizeOptions) error {
+	if len(md) == 0 {
+		return nil
+	}
+
+	out := make(map[string][]string, len(md))
+	legacy := make(map[string][]string)
+
+	for key, values := range md {
+		canonical := strings.ToLower(strings.TrimSpace(key))
+
+		if target, ok := LegacyHeaderAliases[canonical]; ok {
+			legacy[target] = append(legacy[target], values...)
+			continue
+		}
+
+		if options.RejectUnknown && strings.HasPrefix(canonical, HeaderPrefix) {
+			if _, ok := knownHeaders[canonical]; !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownHeader, canonical)
+			}
+		}
+
+		out[canonical] = append(out[canonical], values...)
+	}
+
+	// 旧 key 只在 current key 缺失时补位，避免新旧版本同时写入时出现重复值。
+	for target, values := range legacy {
+		if _, ok := out[target]; !ok {
+			out[target] = values
+		}
+	}
+
+	clear(md)
+	for key, values := range out {
+		md[key] = values
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package constant
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalizeLowercasesAndMapsLegacyKeys(t *testing.T) {
+	md := map[string][]string{
+		"X-Firefly-User-Id":        {"u1"},
+		"x-firefly-app-role-ids":   {"r1", "r2"},
+		"x-firefly-client-ip":      {"10.0.0.1"},
+		"x-firefly-app-tenant-id":  {"legacy"},
+		"x-firefly-tenant-id":      {"current"},
+		"x-custom-business-header": {"keep"},
+	}
+
+	if err := Canonicalize(md, CanonicalizeOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := md[UserId]; len(got) != 1 || got[0] != "u1" {
+		t.Fatalf("unexpected user id: %v", got)
+	}
+	if got := md[RoleIds]; len(got) != 2 || got[0] != "r1" {
+		t.Fatalf("unexpected role ids: %v", got)
+	}
+	if got := md[XRealIp]; len(got) != 1 || got[0] != "10.0.0.1" {
+		t.Fatalf("unexpected real ip: %v", got)
+	}
+	if got := md[TenantId]; len(got) != 1 || got[0] != "current" {
+		t.Fatalf("expected current key to win over legacy alias, got %v", got)
+	}
+	if _, ok := md["x-firefly-app-role-ids"]; ok {
+		t.Fatal("expected legacy key to be removed")
+	}
+	if _, ok := md["x-custom-business-header"]; !ok {
+		t.Fatal("expected non firefly header to be kept")
+	}
+}
+
+func TestCanonicalizeRejectUnknown(t *testing.T) {
+	md := map[string][]string{
+		UserId:                        {"u1"},
+		"x-firefly-gateway-auth-sign": {"old"},
+	}
+
+	err := Canonicalize(md, CanonicalizeOptions{RejectUnknown: true})
+	if !errors.Is(err, ErrUnknownHeader) {
+		t.Fatalf("expected ErrUnknownHeader, got %v", err)
+	}
+	if _, ok := md["x-firefly-gateway-auth-sign"]; !ok {
+		t.Fatal("expected md to stay unchanged on error")
+	}
+
+	delete(md, "x-firefly-gateway-auth-sign")
+	if err := Canonicalize(md, CanonicalizeOptions{RejectUnknown: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
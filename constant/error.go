@@ -0,0 +1,8 @@
+package constant
+
+import "errors"
+
+var (
+	// ErrUnknownHeader 表示 metadata 中出现了 current 协议未定义的 x-firefly-* key。
+	ErrUnknownHeader = errors.New("unknown firefly header")
+)
@@ -100,3 +100,16 @@ const (
 	// ApiPath 表示当前授权检查使用的接口路径，HTTP 为 path，gRPC 为 FullMethod。
 	ApiPath = HeaderPrefix + "api-path"
 )
+
+const (
+	// OperationId 表示长耗时操作 ID，由发起操作的服务写入响应 header，并可在后续调用中透传关联。
+	OperationId = HeaderPrefix + "operation-id"
+	// ConsistencyToken 表示读己之写一致性令牌，例如数据库 LSN 或逻辑版本号，由写接口响应返回、后续读请求携带。
+	ConsistencyToken = HeaderPrefix + "consistency-token"
+	// Shadow 表示镜像流量标记，取值为 "1"；携带该标记的请求响应会被丢弃，下游应避免产生外部副作用。
+	Shadow = HeaderPrefix + "shadow"
+	// RetryAttempt 表示客户端重试序号，首次请求不携带，第一次重试为 "1"；只在当前这一跳有效，不向下游透传。
+	RetryAttempt = HeaderPrefix + "retry-attempt"
+	// IdempotencyKey 表示客户端为写请求生成的幂等键，同一键在窗口内的重试会重放首个成功响应；只在当前这一跳有效，不向下游透传。
+	IdempotencyKey = HeaderPrefix + "idempotency-key"
+)
@@ -1,6 +1,25 @@
 package constant
 
 const (
-	GrpcAccessLog = "[GRPC Access Log]"
-	HttpAccessLog = "[HTTP Access Log]"
+	GrpcAccessLog       = "[GRPC Access Log]"
+	GrpcClientAccessLog = "[GRPC Client Access Log]"
+	GrpcOperationLog    = "[GRPC Operation Log]"
+	HttpAccessLog       = "[HTTP Access Log]"
+)
+
+const (
+	// GrpcStreamStallLog 表示 gRPC 流在检测窗口内没有任何收发进展。
+	GrpcStreamStallLog = "[GRPC Stream Stall]"
+	// GrpcMirrorLog 表示镜像流量调用失败。
+	GrpcMirrorLog = "[GRPC Mirror]"
+	// GrpcPanicLog 表示 gRPC handler 发生 panic 并已被恢复。
+	GrpcPanicLog = "[GRPC Panic]"
+	// GrpcSlowRequestLog 表示 gRPC 请求耗时超过慢请求阈值。
+	GrpcSlowRequestLog = "[GRPC Slow Request]"
+	// GrpcRequestTooLargeLog 表示 gRPC 请求报文超过方法级大小上限而被拒绝。
+	GrpcRequestTooLargeLog = "[GRPC Request Too Large]"
+	// GrpcMissingDeadlineLog 表示入站 gRPC 请求未携带 deadline。
+	GrpcMissingDeadlineLog = "[GRPC Missing Deadline]"
+	// InvocationAddressOverrideLog 表示出站调用存在绕过标准 DNS 的直连地址覆盖。
+	InvocationAddressOverrideLog = "[Invocation Address Override]"
 )
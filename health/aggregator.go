@@ -0,0 +1,207 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCheckTimeout 是单个组件检查的默认超时时间。
+	DefaultCheckTimeout = 2 * time.Second
+	// DefaultInterval 是同步 gRPC 健康状态的默认周期。
+	DefaultInterval = 5 * time.Second
+)
+
+const (
+	// StatusUp 表示组件或整体状态正常。
+	StatusUp = "up"
+	// StatusDegraded 表示仅有非关键组件异常，服务仍可接收流量。
+	StatusDegraded = "degraded"
+	// StatusDown 表示关键组件异常，服务不应接收流量。
+	StatusDown = "down"
+)
+
+// Check 表示一个组件健康检查；返回 nil 表示健康。
+type Check func(ctx context.Context) error
+
+// Component 描述一个参与健康聚合的子系统。
+type Component struct {
+	// Name 表示组件名称，例如 config-watcher、jwks、log-pipeline。
+	Name string
+	// Check 表示组件健康检查函数。
+	Check Check
+	// Liveness 表示组件失败时进程应被判定为不存活，需要重启才能恢复。
+	Liveness bool
+	// Optional 表示组件失败只降级展示，不影响就绪判断。
+	Optional bool
+}
+
+// Options 定义健康聚合器的行为。
+type Options struct {
+	// CheckTimeout 表示单个组件检查的超时时间；未设置时使用 DefaultCheckTimeout。
+	CheckTimeout time.Duration
+	// Interval 表示 Run 同步 gRPC 健康状态的周期；未设置时使用 DefaultInterval。
+	Interval time.Duration
+	// Services 表示需要同步状态的 gRPC 服务名；空字符串代表整个服务端，始终会同步。
+	Services []string
+}
+
+func (o Options) normalize() Options {
+	if o.CheckTimeout <= 0 {
+		o.CheckTimeout = DefaultCheckTimeout
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	return o
+}
+
+// ComponentReport 表示单个组件的检查结果。
+type ComponentReport struct {
+	// Name 表示组件名称。
+	Name string `json:"name"`
+	// Status 表示组件状态，取值为 up / down。
+	Status string `json:"status"`
+	// Error 表示检查失败原因。
+	Error string `json:"error,omitempty"`
+	// Liveness 表示该组件是否参与存活判断。
+	Liveness bool `json:"liveness,omitempty"`
+	// Optional 表示该组件是否为非关键组件。
+	Optional bool `json:"optional,omitempty"`
+	// DurationMs 表示检查耗时（毫秒）。
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Report 表示一次聚合检查的结果。
+type Report struct {
+	// Status 表示整体状态，取值为 up / degraded / down。
+	Status string `json:"status"`
+	// Live 表示进程是否存活。
+	Live bool `json:"live"`
+	// Ready 表示服务是否可以接收流量。
+	Ready bool `json:"ready"`
+	// CheckedAt 表示检查完成时间。
+	CheckedAt time.Time `json:"checked_at"`
+	// Components 表示各组件明细，顺序与注册顺序一致。
+	Components []ComponentReport `json:"components"`
+}
+
+// Aggregator 聚合各子系统的健康检查结果。
+type Aggregator struct {
+	options Options
+
+	mu         sync.RWMutex
+	components []Component
+}
+
+// NewAggregator 创建健康聚合器。
+func NewAggregator(options Options) *Aggregator {
+	return &Aggregator{options: options.normalize()}
+}
+
+// Register 注册一个组件；名称不能为空且不能重复。
+func (a *Aggregator) Register(component Component) error {
+	component.Name = strings.TrimSpace(component.Name)
+	if component.Name == "" {
+		return ErrComponentNameEmpty
+	}
+	if component.Check == nil {
+		return ErrComponentCheckNil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, existing := range a.components {
+		if existing.Name == component.Name {
+			return ErrComponentDuplicate
+		}
+	}
+	a.components = append(a.components, component)
+	return nil
+}
+
+// Evaluate 并发执行所有组件检查并汇总结果。
+func (a *Aggregator) Evaluate(ctx context.Context) Report {
+	a.mu.RLock()
+	components := append([]Component(nil), a.components...)
+	a.mu.RUnlock()
+
+	reports := make([]ComponentReport, len(components))
+	var wg sync.WaitGroup
+	for i, component := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reports[i] = a.runCheck(ctx, component)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:     StatusUp,
+		Live:       true,
+		Ready:      true,
+		CheckedAt:  time.Now(),
+		Components: reports,
+	}
+	for _, item := range reports {
+		if item.Status == StatusUp {
+			continue
+		}
+		if item.Liveness {
+			report.Live = false
+		}
+		if item.Optional {
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+			continue
+		}
+		report.Ready = false
+		report.Status = StatusDown
+	}
+	// 不存活的进程一定不就绪。
+	if !report.Live {
+		report.Ready = false
+		report.Status = StatusDown
+	}
+	return report
+}
+
+// runCheck 在超时约束下执行单个组件检查，并兜住检查函数的 panic。
+func (a *Aggregator) runCheck(ctx context.Context, component Component) (report ComponentReport) {
+	report = ComponentReport{
+		Name:     component.Name,
+		Status:   StatusUp,
+		Liveness: component.Liveness,
+		Optional: component.Optional,
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, a.options.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- panicError{value: recovered}
+			}
+		}()
+		done <- component.Check(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		report.Status = StatusDown
+		report.Error = err.Error()
+	}
+	return report
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestAggregatorEvaluate(t *testing.T) {
+	aggregator := NewAggregator(Options{CheckTimeout: 20 * time.Millisecond})
+	ok := func(context.Context) error { return nil }
+	failed := func(context.Context) error { return errors.New("boom") }
+
+	_ = aggregator.Register(Component{Name: "config", Check: ok, Liveness: true})
+	_ = aggregator.Register(Component{Name: "jwks", Check: failed, Optional: true})
+	if err := aggregator.Register(Component{Name: "config", Check: ok}); !errors.Is(err, ErrComponentDuplicate) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	// 只有非关键组件失败时整体降级但仍就绪。
+	report := aggregator.Evaluate(context.Background())
+	if report.Status != StatusDegraded || !report.Ready || !report.Live {
+		t.Fatalf("unexpected degraded report: %+v", report)
+	}
+
+	// 关键组件超时时不就绪，但仍然存活。
+	_ = aggregator.Register(Component{Name: "db", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	report = aggregator.Evaluate(context.Background())
+	if report.Status != StatusDown || report.Ready || !report.Live {
+		t.Fatalf("unexpected down report: %+v", report)
+	}
+	if report.Components[2].Error == "" {
+		t.Fatalf("expected timeout error detail")
+	}
+}
+
+func TestAggregatorLivenessAndPanic(t *testing.T) {
+	aggregator := NewAggregator(Options{})
+	_ = aggregator.Register(Component{Name: "log-pipeline", Liveness: true, Check: func(context.Context) error {
+		panic("stuck")
+	}})
+
+	report := aggregator.Evaluate(context.Background())
+	if report.Live || report.Ready {
+		t.Fatalf("expected liveness failure to fail both probes: %+v", report)
+	}
+}
+
+func TestHeartbeatCheck(t *testing.T) {
+	var heartbeat Heartbeat
+	check := heartbeat.Check(time.Minute)
+	if err := check(context.Background()); err == nil {
+		t.Fatalf("expected error before first heartbeat")
+	}
+	heartbeat.Beat()
+	if err := check(context.Background()); err != nil {
+		t.Fatalf("unexpected error after heartbeat: %v", err)
+	}
+}
+
+func TestReadinessHandlerAndSync(t *testing.T) {
+	aggregator := NewAggregator(Options{Services: []string{"acme.order.v1.OrderService"}})
+	_ = aggregator.Register(Component{Name: "db", Check: func(context.Context) error { return errors.New("unreachable") }})
+
+	recorder := httptest.NewRecorder()
+	aggregator.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected readiness status: %d", recorder.Code)
+	}
+	var report Report
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil || report.Components[0].Error != "unreachable" {
+		t.Fatalf("unexpected readiness body: %s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	aggregator.LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected liveness status: %d", recorder.Code)
+	}
+
+	server := health.NewServer()
+	aggregator.Sync(context.Background(), server)
+	response, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "acme.order.v1.OrderService"})
+	if err != nil || response.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("unexpected grpc health status: %v %v", response, err)
+	}
+}
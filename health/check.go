@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// panicError 表示检查函数发生 panic。
+type panicError struct {
+	value any
+}
+
+func (e panicError) Error() string {
+	return fmt.Sprintf("health check panic: %v", e.value)
+}
+
+// LastErrorCheck 把 "最近一次错误" 形式的状态接口适配为 Check。
+//
+// 例如 authz.JWKSClient.LastRefreshError：
+//
+//	aggregator.Register(health.Component{
+//		Name:     "jwks",
+//		Check:    health.LastErrorCheck(jwks.LastRefreshError),
+//		Optional: true,
+//	})
+func LastErrorCheck(fn func() error) Check {
+	return func(context.Context) error {
+		return fn()
+	}
+}
+
+// Heartbeat 记录后台循环最近一次活跃时间，用于判断 watcher、日志管道等是否卡死。
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+// Beat 记录一次活跃。
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Check 返回一个检查函数：超过 maxAge 没有心跳时视为失败。
+//
+// 从未心跳过的组件同样视为失败，避免启动后 watcher 从未成功运行却显示健康。
+func (h *Heartbeat) Check(maxAge time.Duration) Check {
+	return func(context.Context) error {
+		last := h.last.Load()
+		if last == 0 {
+			return fmt.Errorf("no heartbeat yet")
+		}
+		if age := time.Since(time.Unix(0, last)); age > maxAge {
+			return fmt.Errorf("last heartbeat %s ago", age.Truncate(time.Millisecond))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,13 @@
+// Package health 聚合各子系统状态，统一输出存活与就绪判断。
+package health
+
+import "errors"
+
+var (
+	// ErrComponentNameEmpty 表示注册组件时未提供名称。
+	ErrComponentNameEmpty = errors.New("health component name is empty")
+	// ErrComponentCheckNil 表示注册组件时未提供检查函数。
+	ErrComponentCheckNil = errors.New("health component check is nil")
+	// ErrComponentDuplicate 表示组件名称已经注册过。
+	ErrComponentDuplicate = errors.New("health component already registered")
+)
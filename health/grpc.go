@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Sync 执行一次聚合检查，并把就绪状态写入 gRPC 健康服务。
+func (a *Aggregator) Sync(ctx context.Context, server *health.Server) Report {
+	report := a.Evaluate(ctx)
+	status := healthpb.HealthCheckResponse_SERVING
+	if !report.Ready {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	server.SetServingStatus("", status)
+	for _, service := range a.options.Services {
+		if service != "" {
+			server.SetServingStatus(service, status)
+		}
+	}
+	return report
+}
+
+// Run 按 Interval 周期同步 gRPC 健康状态，直到 ctx 结束。
+//
+// 停机时应先取消 ctx 再调用 server.Shutdown，避免 Run 把状态重新写回 SERVING。
+func (a *Aggregator) Run(ctx context.Context, server *health.Server) {
+	a.Sync(ctx, server)
+	ticker := time.NewTicker(a.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Sync(ctx, server)
+		}
+	}
+}
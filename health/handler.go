@@ -0,0 +1,30 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LivenessHandler 返回存活探针 HTTP handler；不存活时返回 503，响应体为完整 Report。
+func (a *Aggregator) LivenessHandler() http.Handler {
+	return a.handler(func(report Report) bool { return report.Live })
+}
+
+// ReadinessHandler 返回就绪探针 HTTP handler；不就绪时返回 503，响应体为完整 Report。
+func (a *Aggregator) ReadinessHandler() http.Handler {
+	return a.handler(func(report Report) bool { return report.Ready })
+}
+
+func (a *Aggregator) handler(passed func(Report) bool) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		report := a.Evaluate(request.Context())
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Header().Set("Cache-Control", "no-store")
+		if passed(report) {
+			writer.WriteHeader(http.StatusOK)
+		} else {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(writer).Encode(report)
+	})
+}
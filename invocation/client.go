@@ -0,0 +1,130 @@
+package invocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/fireflycore/go-micro/authz"
+	"google.golang.org/grpc"
+)
+
+// ClientOptions 定义一站式客户端装配所需的配置。
+//
+// 它把 DNSManager、ConnectionManager、UnaryInvoker 与 RemoteServiceManaged
+// 的常见装配收敛到一处，业务侧不再需要按服务手写样板代码。
+type ClientOptions struct {
+	// DNS 表示标准 DNS 默认值；为空时使用 DNSManager 默认配置。
+	DNS *DNSConfig
+	// Services 表示当前服务依赖的远程业务服务。
+	Services []DNS
+	// Timeout 表示统一的远程调用超时时间；未设置时使用 DefaultInvokeTimeout。
+	Timeout time.Duration
+	// ServiceAuthorityProvider 负责为每一跳注入 X-Firefly-Service-Authority。
+	ServiceAuthorityProvider authz.ServiceAuthorityProvider
+	// ContentSubtype 表示出站调用使用的 gRPC content-subtype，例如 json。
+	ContentSubtype string
+	// MethodOverrides 按 full method 覆盖目标服务与超时。
+	MethodOverrides map[string]MethodOverride
+	// DialOptions 表示基础拨号选项；为空时使用 DefaultDialOptions。
+	DialOptions []grpc.DialOption
+	// UnaryInterceptors 表示按顺序链接到每条连接上的 unary 客户端拦截器，例如日志、重试。
+	UnaryInterceptors []grpc.UnaryClientInterceptor
+	// StreamInterceptors 表示按顺序链接到每条连接上的 stream 客户端拦截器。
+	StreamInterceptors []grpc.StreamClientInterceptor
+	// IdleTimeout 表示连接空闲淘汰时长，语义同 ConnectionManagerOptions.IdleTimeout。
+	IdleTimeout time.Duration
+	// MaxAge 表示连接最长使用时长，语义同 ConnectionManagerOptions.MaxAge。
+	MaxAge time.Duration
+	// DialFunc 是可选的底层拨号实现，主要用于测试。
+	DialFunc DialFunc
+}
+
+// dialOptions 组装基础拨号选项与客户端拦截器。
+func (o ClientOptions) dialOptions() []grpc.DialOption {
+	base := o.DialOptions
+	if len(base) == 0 {
+		base = DefaultDialOptions()
+	}
+	options := append(make([]grpc.DialOption, 0, len(base)+2), base...)
+	if len(o.UnaryInterceptors) > 0 {
+		options = append(options, grpc.WithChainUnaryInterceptor(o.UnaryInterceptors...))
+	}
+	if len(o.StreamInterceptors) > 0 {
+		options = append(options, grpc.WithChainStreamInterceptor(o.StreamInterceptors...))
+	}
+	return options
+}
+
+// Client 是装配完成的远程业务服务客户端。
+//
+// 它内嵌 RemoteServiceManaged，可以直接按服务名 Invoke 或派生 Caller；
+// 同时持有底层 ConnectionManager，停机时通过 Close 统一释放连接。
+type Client struct {
+	*RemoteServiceManaged
+
+	// manager 保存底层连接管理器。
+	manager *ConnectionManager
+	// invoker 保存统一调用器。
+	invoker *UnaryInvoker
+}
+
+// NewClient 根据 ClientOptions 装配 DNS、连接复用、调用器与远程服务注册表。
+func NewClient(options ClientOptions) (*Client, error) {
+	manager, err := NewConnectionManager(ConnectionManagerOptions{
+		DNSManager:  NewDNSManager(options.DNS),
+		DialFunc:    options.DialFunc,
+		DialOptions: options.dialOptions(),
+		IdleTimeout: options.IdleTimeout,
+		MaxAge:      options.MaxAge,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invoker := NewUnaryInvoker(manager, options.Timeout).
+		WithServiceAuthorityProvider(options.ServiceAuthorityProvider).
+		WithContentSubtype(options.ContentSubtype).
+		WithMethodOverrides(options.MethodOverrides)
+
+	return &Client{
+		RemoteServiceManaged: NewRemoteServiceManaged(invoker, options.Services...),
+		manager:              manager,
+		invoker:              invoker,
+	}, nil
+}
+
+// Conn 返回指定业务服务的可复用 grpc.ClientConn，供 protoc 生成的 stub 或流式调用使用。
+//
+// 返回的连接仍归 ConnectionManager 管理：配置 IdleTimeout / MaxAge 时，连接被空闲淘汰或轮换后
+// 经过 DrainTimeout 关闭，长期持有的连接随后会返回 "grpc: the client connection is closing"。
+// 调用方不应缓存返回值或基于它创建的 stub，而应在每次使用时重新调用 Conn；命中缓存时 Conn 只是一次 map 查找，
+// 同时会刷新空闲计时并在到期时换上新连接。流式调用在流结束前不会再次调用 Conn，IdleTimeout 应大于最长的流持续时间。
+//
+// 通过 Conn 直接发起的调用只经过连接上的拦截器，
+// 不会经过 UnaryInvoker 的 authority 注入与统一超时，标准 unary 调用应优先使用 Invoke。
+func (c *Client) Conn(ctx context.Context, serviceName string) (*grpc.ClientConn, error) {
+	if c == nil || c.manager == nil {
+		return nil, ErrInvokerDialerIsNil
+	}
+	dns, err := c.lookup(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return c.manager.Dial(ctx, dns)
+}
+
+// Invoker 返回客户端共用的 UnaryInvoker。
+func (c *Client) Invoker() *UnaryInvoker {
+	if c == nil {
+		return nil
+	}
+	return c.invoker
+}
+
+// Close 关闭客户端持有的全部连接。
+func (c *Client) Close() error {
+	if c == nil || c.manager == nil {
+		return nil
+	}
+	return c.manager.Close()
+}
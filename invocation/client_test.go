@@ -0,0 +1,133 @@
+package invocation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestNewClient_ComposesInterceptorsAndServices(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	var intercepted []string
+	var dialedTarget string
+	client, err := NewClient(ClientOptions{
+		Services: []DNS{{Service: "auth"}},
+		DialOptions: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+		},
+		UnaryInterceptors: []grpc.UnaryClientInterceptor{
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				intercepted = append(intercepted, method)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			},
+		},
+		DialFunc: func(ctx context.Context, target Target, options []grpc.DialOption) (*grpc.ClientConn, error) {
+			dialedTarget = target.GRPCTarget()
+			return grpc.NewClient("passthrough:///bufnet", options...)
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	resp := &healthpb.HealthCheckResponse{}
+	if err := client.Invoke(context.Background(), "auth", healthpb.Health_Check_FullMethodName, &healthpb.HealthCheckRequest{}, resp); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("unexpected health status: %s", resp.Status)
+	}
+	if len(intercepted) != 1 || intercepted[0] != healthpb.Health_Check_FullMethodName {
+		t.Fatalf("expected client interceptor to run once, got %v", intercepted)
+	}
+	if dialedTarget != "dns:///auth.default.svc.cluster.local:9090" {
+		t.Fatalf("unexpected dialed target: %s", dialedTarget)
+	}
+
+	// Conn 复用 Invoke 建立的同一条连接。
+	conn, err := client.Conn(context.Background(), "auth")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("expected generated stub call to succeed, got %v", err)
+	}
+	if _, err := client.Conn(context.Background(), "unknown"); !errors.Is(err, ErrRemoteServiceNotFound) {
+		t.Fatalf("expected ErrRemoteServiceNotFound, got %v", err)
+	}
+}
+
+func TestClientConn_MustBeFetchedPerUseWithEviction(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		Services:    []DNS{{Service: "auth"}},
+		IdleTimeout: time.Minute,
+		MaxAge:      time.Hour,
+		DialFunc: func(ctx context.Context, target Target, options []grpc.DialOption) (*grpc.ClientConn, error) {
+			return grpc.NewClient("passthrough:///bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	now := time.Unix(1710000000, 0)
+	client.manager.now = func() time.Time { return now }
+
+	// 长期持有的连接在空闲淘汰并经过排空期后被关闭。
+	held, err := client.Conn(context.Background(), "auth")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	client.manager.sweep(now.Add(2 * time.Minute))
+	client.manager.sweep(now.Add(2*time.Minute + DefaultDrainTimeout))
+	if held.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected held connection to be closed after idle eviction, got %s", held.GetState())
+	}
+
+	// 每次使用前重新调用 Conn 会拿到可用的新连接。
+	now = now.Add(2 * time.Minute)
+	fresh, err := client.Conn(context.Background(), "auth")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if fresh == held || fresh.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected a fresh open connection")
+	}
+
+	// 持续调用 Conn 会刷新空闲计时；到达 MaxAge 后换上新连接，旧连接在排空期后关闭。
+	now = now.Add(30 * time.Second)
+	if again, _ := client.Conn(context.Background(), "auth"); again != fresh {
+		t.Fatalf("expected cached connection to be reused")
+	}
+	now = now.Add(time.Hour)
+	rotated, err := client.Conn(context.Background(), "auth")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if rotated == fresh {
+		t.Fatalf("expected connection to be rotated after MaxAge")
+	}
+	client.manager.sweep(now.Add(DefaultDrainTimeout))
+	if fresh.GetState() != connectivity.Shutdown || rotated.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected only the retired connection to be closed")
+	}
+}
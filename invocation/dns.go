@@ -1,8 +1,17 @@
 package invocation
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
 )
 
 const (
@@ -16,6 +25,10 @@ const (
 	DefaultClusterDomain = "cluster.local"
 	// DefaultServicePort 是业务服务默认使用的 gRPC 端口。
 	DefaultServicePort = 9090
+	// AddressOverrideResolverScheme 是直连地址覆盖使用的 resolver scheme，不经过 DNS 解析。
+	AddressOverrideResolverScheme = "passthrough"
+	// AddressOverridesEnv 是直连地址覆盖的环境变量名，格式为 "auth=127.0.0.1:9090,iam=localhost:9091"。
+	AddressOverridesEnv = "FIREFLY_ADDRESS_OVERRIDES"
 )
 
 // DNS 表示一个远程业务服务的标准 DNS 配置。
@@ -108,6 +121,16 @@ type DNSConfig struct {
 	DefaultPort uint16
 	// ResolverScheme 表示默认 gRPC resolver scheme。
 	ResolverScheme string
+	// AddressOverrides 按业务服务名强制直连固定 host:port，绕过标准 DNS。
+	//
+	// 用于本地调试连接远程集群或故障时的紧急切流，生产常态不应配置。
+	AddressOverrides map[string]string
+	// EnvAddressOverrides 为 true 时合并 FIREFLY_ADDRESS_OVERRIDES 环境变量，同一服务以显式配置为准。
+	//
+	// 默认关闭，避免生产环境中残留的环境变量被静默生效。
+	EnvAddressOverrides bool
+	// Logger 在存在直连地址覆盖时输出 warn 日志，为空时不输出。
+	Logger *logger.ServerLogger
 }
 
 // normalize 补齐 DNSConfig 的默认值。
@@ -132,10 +155,120 @@ func (c DNSConfig) normalize() *DNSConfig {
 	if strings.TrimSpace(c.ResolverScheme) == "" {
 		c.ResolverScheme = DefaultResolverScheme
 	}
+	// 显式开启后合并环境变量中的直连地址，使紧急切流无需改代码即可生效。
+	if c.EnvAddressOverrides {
+		c.AddressOverrides = mergeEnvAddressOverrides(c.AddressOverrides)
+	}
 	return &c
 }
 
-// defaultDNSConfig 保存一份进程级默认配置，避免 nil 配置场景重复归一化。
+// mergeEnvAddressOverrides 把 FIREFLY_ADDRESS_OVERRIDES 合并进显式配置，返回新的 map，不修改调用方传入的 map。
+//
+// 显式配置优先；地址统一在 NewDNSManager 中预解析，非法条目通过 DNSManager.Validate 暴露，
+// 缺少服务名的条目无法归属任何服务，以空服务名保留，同样由 Validate 报告。
+func mergeEnvAddressOverrides(explicit map[string]string) map[string]string {
+	raw, ok := os.LookupEnv(AddressOverridesEnv)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return explicit
+	}
+	merged := make(map[string]string, len(explicit))
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		service, address, ok := strings.Cut(item, "=")
+		if !ok {
+			address = ""
+		}
+		merged[strings.TrimSpace(service)] = strings.TrimSpace(address)
+	}
+	for service, address := range explicit {
+		merged[strings.TrimSpace(service)] = address
+	}
+	return merged
+}
+
+// ParseAddressOverrides 解析 "service=host:port,service=host:port" 形式的直连地址覆盖。
+func ParseAddressOverrides(raw string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		service, address, ok := strings.Cut(item, "=")
+		service = strings.TrimSpace(service)
+		address = strings.TrimSpace(address)
+		if !ok || service == "" {
+			return nil, fmt.Errorf("%w: %q", ErrAddressOverrideInvalid, item)
+		}
+		if _, err := parseOverrideTarget(address); err != nil {
+			return nil, err
+		}
+		overrides[service] = address
+	}
+	return overrides, nil
+}
+
+// AddressOverridesFromEnv 读取并严格校验 FIREFLY_ADDRESS_OVERRIDES 环境变量；未设置时返回 nil。
+//
+// DNSConfig.EnvAddressOverrides 开启时归一化会自动合并该环境变量，这里只用于启动时提前校验格式。
+func AddressOverridesFromEnv() (map[string]string, error) {
+	raw, ok := os.LookupEnv(AddressOverridesEnv)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	return ParseAddressOverrides(raw)
+}
+
+// parseOverrideTarget 把 host:port 解析为直连 Target。
+func parseOverrideTarget(address string) (*Target, error) {
+	host, portText, err := net.SplitHostPort(strings.TrimSpace(address))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrAddressOverrideInvalid, address)
+	}
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil || port == 0 || host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrAddressOverrideInvalid, address)
+	}
+	target := &Target{
+		ResolverScheme: AddressOverrideResolverScheme,
+		Host:           host,
+		Port:           uint16(port),
+	}
+	target.cacheDerivedStrings()
+	return target, nil
+}
+
+// addressOverrideContextKey 是单次调用直连地址覆盖在 context 中的 key。
+type addressOverrideContextKey struct{}
+
+// WithAddressOverride 返回携带单次调用直连地址的 ctx。
+//
+// 经 ConnectionManager.Dial 发起的调用（包括 Invoke）会忽略服务 DNS，直接拨号到 address（host:port），
+// 优先级高于 DNSConfig.AddressOverrides，用于只把个别请求切到本地或指定实例排障。
+func WithAddressOverride(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, addressOverrideContextKey{}, strings.TrimSpace(address))
+}
+
+// addressOverrideFromContext 读取单次调用直连地址。
+func addressOverrideFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	address, ok := ctx.Value(addressOverrideContextKey{}).(string)
+	return address, ok && address != ""
+}
+
+// addressOverride 保存预解析的直连目标；地址非法时记录错误，在 Build 时返回。
+type addressOverride struct {
+	address string
+	target  *Target
+	err     error
+}
+
+// defaultDNSConfig 保存一份进程级默认配置，供 nil 管理器读取。
 var defaultDNSConfig = DNSConfig{}.normalize()
 
 // DNSManager 负责把结构化的 DNS 转成最终 Target。
@@ -144,19 +277,73 @@ var defaultDNSConfig = DNSConfig{}.normalize()
 // 它不做实例发现、不做节点选择，也不做后端适配。
 type DNSManager struct {
 	config *DNSConfig
+	// overrides 保存按服务名预解析的直连地址覆盖。
+	overrides map[string]addressOverride
 }
 
 // NewDNSManager 创建一个标准 DNS 管理器。
 func NewDNSManager(config *DNSConfig) *DNSManager {
 	// 若调用方没有传配置，则使用一份空配置走默认值补齐逻辑。
 	if config == nil {
-		// 直接复用进程级默认配置指针，减少重复分配。
-		return &DNSManager{config: defaultDNSConfig}
+		config = &DNSConfig{}
 	}
-	// 非 nil 配置仍然在创建时完成一次归一化。
-	return &DNSManager{
+	// 创建时完成一次归一化，同时合并当前环境变量中的直连地址。
+	manager := &DNSManager{
 		config: config.normalize(),
 	}
+	// 直连地址在创建时预解析，避免 Build 热路径重复解析字符串。
+	if overrides := manager.config.AddressOverrides; len(overrides) > 0 {
+		manager.overrides = make(map[string]addressOverride, len(overrides))
+		for service, address := range overrides {
+			target, err := parseOverrideTarget(address)
+			if err == nil && strings.TrimSpace(service) == "" {
+				err = fmt.Errorf("%w: %q", ErrAddressOverrideInvalid, "="+address)
+			}
+			manager.overrides[strings.TrimSpace(service)] = addressOverride{address: address, target: target, err: err}
+		}
+		manager.warnAddressOverrides()
+	}
+	return manager
+}
+
+// Validate 返回第一个非法的直连地址覆盖，按服务名排序以保证结果稳定；全部合法时返回 nil。
+//
+// NewConnectionManager 会在创建时调用它，使配置错误在启动阶段暴露，而不是等到首次调用对应服务。
+func (m *DNSManager) Validate() error {
+	if m == nil {
+		return nil
+	}
+	for _, service := range m.overrideServices() {
+		if err := m.overrides[service].err; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overrideServices 返回按名称排序的覆盖服务列表。
+func (m *DNSManager) overrideServices() []string {
+	services := make([]string, 0, len(m.overrides))
+	for service := range m.overrides {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// warnAddressOverrides 在存在直连地址覆盖时输出一条 warn 日志，提醒这些服务绕过了标准 DNS。
+func (m *DNSManager) warnAddressOverrides() {
+	if m.config.Logger == nil {
+		return
+	}
+	entries := make([]string, 0, len(m.overrides))
+	for _, service := range m.overrideServices() {
+		entries = append(entries, service+"="+m.overrides[service].address)
+	}
+	m.config.Logger.WithContextWarn(context.Background(), constant.InvocationAddressOverrideLog,
+		zap.Strings("overrides", entries),
+		zap.Bool("env", m.config.EnvAddressOverrides),
+	)
 }
 
 // configOrDefault 返回当前管理器可用的配置指针。
@@ -218,6 +405,16 @@ func (m *DNSManager) Build(dns *DNS) (*Target, error) {
 	if err := validateDNS(dns); err != nil {
 		return &Target{}, err
 	}
+	// 命中直连地址覆盖时跳过标准 DNS 拼装。
+	if m != nil && m.overrides != nil {
+		if override, ok := m.overrides[strings.TrimSpace(dns.Service)]; ok {
+			if override.err != nil {
+				return &Target{}, override.err
+			}
+			target := *override.target
+			return &target, nil
+		}
+	}
 	port, err := effectivePort(dns, config.DefaultPort)
 	if err != nil {
 		return &Target{}, err
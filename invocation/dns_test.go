@@ -1,7 +1,14 @@
 package invocation
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestDNS_Normalize_FillsDefaults(t *testing.T) {
@@ -146,3 +153,124 @@ func TestValidateDNS_RejectsNilDNS(t *testing.T) {
 		t.Fatalf("expected %v, got %v", ErrServiceNameEmpty, err)
 	}
 }
+
+func TestDNSManager_Build_UsesAddressOverride(t *testing.T) {
+	manager := NewDNSManager(&DNSConfig{
+		AddressOverrides: map[string]string{
+			"auth": "127.0.0.1:19090",
+			"iam":  "not-an-address",
+		},
+	})
+
+	target, err := manager.Build(&DNS{Service: "auth"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if target.GRPCTarget() != "passthrough:///127.0.0.1:19090" {
+		t.Fatalf("unexpected override target: %s", target.GRPCTarget())
+	}
+
+	if _, err := manager.Build(&DNS{Service: "iam"}); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected ErrAddressOverrideInvalid, got %v", err)
+	}
+
+	// 未覆盖的服务仍走标准 DNS。
+	target, err = manager.Build(&DNS{Service: "order"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if target.GRPCTarget() != "dns:///order.default.svc.cluster.local:9090" {
+		t.Fatalf("unexpected dns target: %s", target.GRPCTarget())
+	}
+}
+
+func TestAddressOverridesFromEnv(t *testing.T) {
+	t.Setenv(AddressOverridesEnv, "auth=127.0.0.1:19090, iam=[::1]:19091")
+	overrides, err := AddressOverridesFromEnv()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if overrides["auth"] != "127.0.0.1:19090" || overrides["iam"] != "[::1]:19091" {
+		t.Fatalf("unexpected overrides: %v", overrides)
+	}
+
+	if _, err := ParseAddressOverrides("auth"); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected ErrAddressOverrideInvalid, got %v", err)
+	}
+}
+
+func TestNewDNSManager_MergesEnvAddressOverrides(t *testing.T) {
+	t.Setenv(AddressOverridesEnv, "auth=127.0.0.1:19090,iam=127.0.0.1:19091")
+
+	// 未显式开启时忽略环境变量，避免残留配置在生产静默生效。
+	target, err := NewDNSManager(nil).Build(&DNS{Service: "auth"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if target.GRPCTarget() != "dns:///auth.default.svc.cluster.local:9090" {
+		t.Fatalf("expected env overrides to be ignored without opt-in, got %s", target.GRPCTarget())
+	}
+
+	// 显式配置优先于环境变量，且调用方传入的 map 不被修改。
+	explicit := map[string]string{"iam": "10.0.0.1:9090"}
+	manager := NewDNSManager(&DNSConfig{AddressOverrides: explicit, EnvAddressOverrides: true})
+	target, err = manager.Build(&DNS{Service: "iam"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if target.GRPCTarget() != "passthrough:///10.0.0.1:9090" {
+		t.Fatalf("expected explicit override to win, got %s", target.GRPCTarget())
+	}
+	if len(explicit) != 1 {
+		t.Fatalf("expected caller map to stay untouched, got %v", explicit)
+	}
+	if target, _ := manager.Build(&DNS{Service: "auth"}); target.GRPCTarget() != "passthrough:///127.0.0.1:19090" {
+		t.Fatalf("expected env override to be merged, got %s", target.GRPCTarget())
+	}
+}
+
+func TestDNSManager_ValidateReportsMalformedEnvOverrides(t *testing.T) {
+	t.Setenv(AddressOverridesEnv, "auth=127.0.0.1:19090,iam")
+
+	manager := NewDNSManager(&DNSConfig{EnvAddressOverrides: true})
+	if err := manager.Validate(); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected ErrAddressOverrideInvalid, got %v", err)
+	}
+	if _, err := NewConnectionManager(ConnectionManagerOptions{DNSManager: manager}); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected connection manager to reject malformed overrides, got %v", err)
+	}
+
+	t.Setenv(AddressOverridesEnv, "=127.0.0.1:19090")
+	if err := NewDNSManager(&DNSConfig{EnvAddressOverrides: true}).Validate(); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected entry without service to be rejected, got %v", err)
+	}
+
+	if err := NewDNSManager(nil).Validate(); err != nil {
+		t.Fatalf("expected nil error without overrides, got %v", err)
+	}
+}
+
+func TestNewDNSManager_WarnsWhenAddressOverridesActive(t *testing.T) {
+	t.Setenv(AddressOverridesEnv, "auth=127.0.0.1:19090")
+	core, observed := observer.New(zapcore.InfoLevel)
+
+	NewDNSManager(&DNSConfig{
+		AddressOverrides:    map[string]string{"iam": "127.0.0.1:19091"},
+		EnvAddressOverrides: true,
+		Logger:              logger.NewServerLogger(zap.New(core)),
+	})
+
+	entries := observed.FilterMessage(constant.InvocationAddressOverrideLog).All()
+	if len(entries) != 1 || entries[0].Level != zapcore.WarnLevel {
+		t.Fatalf("expected one warn log, got %v", entries)
+	}
+	overrides, _ := entries[0].ContextMap()["overrides"].([]any)
+	if len(overrides) != 2 || overrides[0] != "auth=127.0.0.1:19090" || overrides[1] != "iam=127.0.0.1:19091" {
+		t.Fatalf("unexpected overrides field: %v", entries[0].ContextMap()["overrides"])
+	}
+
+	NewDNSManager(&DNSConfig{Logger: logger.NewServerLogger(zap.New(core))})
+	if observed.FilterMessage(constant.InvocationAddressOverrideLog).Len() != 1 {
+		t.Fatal("expected no warn log without overrides")
+	}
+}
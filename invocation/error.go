@@ -24,6 +24,8 @@ var (
 	ErrInvokerDialerIsNil = errors.New("invoker dialer is nil")
 	// ErrInvokeMethodEmpty 表示调用方法名为空。
 	ErrInvokeMethodEmpty = errors.New("invoke method is empty")
+	// ErrAddressOverrideInvalid 表示直连地址覆盖不是合法的 host:port。
+	ErrAddressOverrideInvalid = errors.New("address override is invalid")
 	// ErrRemoteServiceNotFound 表示未找到指定远程业务服务。
 	ErrRemoteServiceNotFound = errors.New("remote service not found")
 )
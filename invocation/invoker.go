@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/fireflycore/go-micro/authz"
+	"github.com/fireflycore/go-micro/rpcerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -66,6 +67,22 @@ type UnaryInvoker struct {
 	Timeout time.Duration
 	// InvokeFunc 是可选依赖；若为空，则默认调用 grpc.ClientConn.Invoke。
 	InvokeFunc UnaryInvokeFunc
+	// ContentSubtype 表示出站调用使用的 gRPC content-subtype，例如 json；为空时使用默认 protobuf。
+	//
+	// 对应编解码器必须已通过 codec.Register / codec.RegisterJSON 注册。
+	ContentSubtype string
+	// MethodOverrides 按 full method 覆盖目标服务与超时，例如把导出接口路由到独立部署的服务并放宽超时。
+	MethodOverrides map[string]MethodOverride
+}
+
+// MethodOverride 表示单个 full method 的调用覆盖。
+type MethodOverride struct {
+	// DNS 表示覆盖后的目标业务服务；为空时沿用调用方传入的 DNS。
+	DNS *DNS
+	// Timeout 表示覆盖后的调用超时；非正数时沿用 UnaryInvoker.Timeout。
+	Timeout time.Duration
+	// Address 表示该方法直连的 host:port，绕过服务 DNS；ctx 中的 WithAddressOverride 优先级更高。
+	Address string
 }
 
 // NewUnaryInvoker 创建统一调用器。
@@ -88,6 +105,44 @@ func (u *UnaryInvoker) WithServiceAuthorityProvider(provider authz.ServiceAuthor
 	return u
 }
 
+// WithContentSubtype 为 invoker 指定出站调用使用的 gRPC content-subtype。
+func (u *UnaryInvoker) WithContentSubtype(subtype string) *UnaryInvoker {
+	// nil receiver 保持链式调用安全。
+	if u == nil {
+		return nil
+	}
+	// 服务端会按同一个 content-subtype 选择编解码器并原样应答。
+	u.ContentSubtype = subtype
+	// 返回自身，便于启动装配中链式配置。
+	return u
+}
+
+// WithMethodOverrides 为 invoker 装配按 full method 的目标服务与超时覆盖。
+//
+// 传入的 map 会被复制，调用方后续修改不会影响 invoker。
+func (u *UnaryInvoker) WithMethodOverrides(overrides map[string]MethodOverride) *UnaryInvoker {
+	// nil receiver 保持链式调用安全。
+	if u == nil {
+		return nil
+	}
+	if len(overrides) == 0 {
+		u.MethodOverrides = nil
+		return u
+	}
+	// 复制一份，避免调用方并发修改 map 影响热路径读取。
+	copied := make(map[string]MethodOverride, len(overrides))
+	for method, override := range overrides {
+		if override.DNS != nil {
+			dns := *override.DNS
+			override.DNS = &dns
+		}
+		copied[method] = override
+	}
+	u.MethodOverrides = copied
+	// 返回自身，便于启动装配中链式配置。
+	return u
+}
+
 // Invoke 执行一次标准 unary 调用。
 func (u *UnaryInvoker) Invoke(ctx context.Context, dns *DNS, method string, req any, resp any, callOptions ...grpc.CallOption) error {
 	if u == nil || u.Dialer == nil {
@@ -104,6 +159,19 @@ func (u *UnaryInvoker) Invoke(ctx context.Context, dns *DNS, method string, req
 	}
 	// 统一计算一次 timeout，避免重复归一化。
 	timeout := normalizeInvokeTimeout(u.Timeout)
+	// 命中方法级覆盖时替换目标服务、直连地址与超时。
+	dialCtx := ctx
+	if override, ok := u.MethodOverrides[method]; ok {
+		if override.DNS != nil {
+			dns = override.DNS
+		}
+		if override.Timeout > 0 {
+			timeout = override.Timeout
+		}
+		if _, ok := addressOverrideFromContext(ctx); !ok && override.Address != "" {
+			dialCtx = WithAddressOverride(ctx, override.Address)
+		}
+	}
 
 	// 直接复用当前链路 metadata，清理旧授权上下文，并覆盖当前服务 authority。
 	resolvedMetadata, err := resolveOutgoingMetadata(ctx, u.ServiceAuthorityProvider)
@@ -112,7 +180,7 @@ func (u *UnaryInvoker) Invoke(ctx context.Context, dns *DNS, method string, req
 	}
 
 	// 然后按业务服务 DNS 获取可复用连接。
-	conn, err := u.Dialer.Dial(ctx, dns)
+	conn, err := u.Dialer.Dial(dialCtx, dns)
 	if err != nil {
 		return err
 	}
@@ -121,8 +189,21 @@ func (u *UnaryInvoker) Invoke(ctx context.Context, dns *DNS, method string, req
 	outCtx, cancel := newOutgoingCallContextWithOwnedMetadata(ctx, resolvedMetadata, timeout)
 	defer cancel()
 
-	// 使用最终的 invoke 实现发起调用。
-	return invokeFunc(outCtx, conn, method, req, resp, callOptions...)
+	// 配置了 content-subtype 时追加到调用选项末尾，调用方显式传入的同类选项优先级更低。
+	if u.ContentSubtype != "" {
+		callOptions = append(callOptions[:len(callOptions):len(callOptions)], grpc.CallContentSubtype(u.ContentSubtype))
+	}
+
+	// 使用最终的 invoke 实现发起调用，失败时标记错误来自下游，便于入口访问日志区分错误来源。
+	return rpcerror.MarkUpstream(invokeFunc(outCtx, conn, method, req, resp, callOptions...), upstreamName(dns))
+}
+
+// upstreamName 返回用于错误来源标记的下游业务服务名。
+func upstreamName(dns *DNS) string {
+	if dns == nil {
+		return ""
+	}
+	return dns.Service
 }
 
 // resolveOutgoingMetadata 从当前链路解析最终出站 metadata，并覆盖当前服务 authority。
@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type testDialer struct {
@@ -391,3 +394,132 @@ type fixedServiceAuthorityProvider string
 func (p fixedServiceAuthorityProvider) ServiceAuthority(context.Context) (string, error) {
 	return string(p), nil
 }
+
+func TestUnaryInvoker_Invoke_AppendsContentSubtype(t *testing.T) {
+	var got []grpc.CallOption
+	invoker := (&UnaryInvoker{
+		Dialer: testDialer{conn: &grpc.ClientConn{}},
+		InvokeFunc: func(ctx context.Context, conn *grpc.ClientConn, method string, req any, resp any, options ...grpc.CallOption) error {
+			got = options
+			return nil
+		},
+	}).WithContentSubtype("json")
+
+	err := invoker.Invoke(context.Background(), &DNS{Service: "auth"}, "/acme.auth.v1.AuthService/Check", struct{}{}, &struct{}{})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected one call option, got %d", len(got))
+	}
+	option, ok := got[0].(grpc.ContentSubtypeCallOption)
+	if !ok || option.ContentSubtype != "json" {
+		t.Fatalf("unexpected content subtype option: %#v", got[0])
+	}
+}
+
+func TestUnaryInvoker_Invoke_MarksUpstreamError(t *testing.T) {
+	invoker := &UnaryInvoker{
+		Dialer: testDialer{},
+		InvokeFunc: func(ctx context.Context, conn *grpc.ClientConn, method string, req any, resp any, options ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "user not found")
+		},
+	}
+
+	err := invoker.Invoke(context.Background(), &DNS{Service: "auth"}, "/acme.auth.v1.UserService/Get", nil, nil)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected code to be preserved, got %v", err)
+	}
+	if upstream, ok := rpcerror.UpstreamFromError(err); !ok || upstream != "auth" {
+		t.Fatalf("expected upstream marker, got %q %v", upstream, ok)
+	}
+}
+
+func TestUnaryInvoker_Invoke_AppliesMethodOverrides(t *testing.T) {
+	var dialed string
+	overrides := map[string]MethodOverride{
+		"/acme.order.v1.OrderService/Export": {
+			DNS:     &DNS{Service: "order-export", Namespace: "batch"},
+			Timeout: 30 * time.Second,
+		},
+	}
+	invoker := NewUnaryInvoker(dialerFunc(func(ctx context.Context, dns *DNS) (*grpc.ClientConn, error) {
+		dialed = dns.Service + "." + dns.Namespace
+		return &grpc.ClientConn{}, nil
+	}), time.Second).WithMethodOverrides(overrides)
+	invoker.InvokeFunc = func(ctx context.Context, conn *grpc.ClientConn, method string, req any, resp any, options ...grpc.CallOption) error {
+		deadline, _ := ctx.Deadline()
+		if remaining := time.Until(deadline); remaining < 20*time.Second {
+			t.Fatalf("expected overridden timeout, got %s", remaining)
+		}
+		return nil
+	}
+	// 复制后修改原 map 不影响 invoker。
+	delete(overrides, "/acme.order.v1.OrderService/Export")
+
+	service := &DNS{Service: "order", Namespace: "default"}
+	if err := invoker.Invoke(context.Background(), service, "/acme.order.v1.OrderService/Export", struct{}{}, &struct{}{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialed != "order-export.batch" {
+		t.Fatalf("expected overridden target, got %s", dialed)
+	}
+
+	invoker.InvokeFunc = func(ctx context.Context, conn *grpc.ClientConn, method string, req any, resp any, options ...grpc.CallOption) error {
+		return nil
+	}
+	if err := invoker.Invoke(context.Background(), service, "/acme.order.v1.OrderService/Get", struct{}{}, &struct{}{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialed != "order.default" {
+		t.Fatalf("expected default target for non-overridden method, got %s", dialed)
+	}
+}
+
+func TestUnaryInvoker_Invoke_AppliesMethodAddressOverride(t *testing.T) {
+	var dialed string
+	invoker := NewUnaryInvoker(dialerFunc(func(ctx context.Context, dns *DNS) (*grpc.ClientConn, error) {
+		dialed, _ = addressOverrideFromContext(ctx)
+		return &grpc.ClientConn{}, nil
+	}), time.Second).WithMethodOverrides(map[string]MethodOverride{
+		"/acme.order.v1.OrderService/Export": {Address: "127.0.0.1:19090"},
+	})
+	invoker.InvokeFunc = func(ctx context.Context, conn *grpc.ClientConn, method string, req any, resp any, options ...grpc.CallOption) error {
+		return nil
+	}
+
+	service := &DNS{Service: "order"}
+	if err := invoker.Invoke(context.Background(), service, "/acme.order.v1.OrderService/Export", struct{}{}, &struct{}{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialed != "127.0.0.1:19090" {
+		t.Fatalf("expected method address override, got %q", dialed)
+	}
+
+	// 单次调用的直连地址优先于方法级覆盖。
+	ctx := WithAddressOverride(context.Background(), "127.0.0.1:19091")
+	if err := invoker.Invoke(ctx, service, "/acme.order.v1.OrderService/Export", struct{}{}, &struct{}{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialed != "127.0.0.1:19091" {
+		t.Fatalf("expected context address override to win, got %q", dialed)
+	}
+
+	if err := invoker.Invoke(context.Background(), service, "/acme.order.v1.OrderService/Get", struct{}{}, &struct{}{}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialed != "" {
+		t.Fatalf("expected no address override for other methods, got %q", dialed)
+	}
+}
+
+// dialerFunc 把函数适配为 Dialer，便于测试观察目标 DNS。
+type dialerFunc func(ctx context.Context, dns *DNS) (*grpc.ClientConn, error)
+
+func (f dialerFunc) Dial(ctx context.Context, dns *DNS) (*grpc.ClientConn, error) {
+	return f(ctx, dns)
+}
+
+func (f dialerFunc) Close() error {
+	return nil
+}
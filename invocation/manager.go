@@ -3,6 +3,8 @@ package invocation
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
@@ -25,8 +27,23 @@ type ConnectionManagerOptions struct {
 	DialFunc DialFunc
 	// DialOptions 表示创建 grpc.ClientConn 时使用的附加选项。
 	DialOptions []grpc.DialOption
+	// IdleTimeout 表示连接超过该时长没有被 Dial 取用时移出缓存，并在 DrainTimeout 后关闭；0 表示不淘汰。
+	// 长连接流式调用期间不会再次 Dial，因此该值应大于最长的流持续时间。
+	IdleTimeout time.Duration
+	// MaxAge 表示连接最长使用时长；到期后下一次 Dial 会创建新连接替换旧连接，0 表示不轮换。
+	// 轮换可以让客户端定期重新解析 DNS 并重新建立 TLS，避免长期粘在同一批后端上。
+	MaxAge time.Duration
+	// DrainTimeout 表示被轮换或空闲淘汰的旧连接延迟关闭的时长，留给在途请求结束；未设置时使用 DefaultDrainTimeout。
+	DrainTimeout time.Duration
 }
 
+const (
+	// DefaultDrainTimeout 是轮换或空闲淘汰旧连接的默认延迟关闭时长。
+	DefaultDrainTimeout = 30 * time.Second
+	// minSweepInterval 是后台淘汰巡检的最小周期，避免配置过小时空转。
+	minSweepInterval = time.Second
+)
+
 type connectionManagerConfig struct {
 	// dnsManager 保存标准 DNS 目标构建器。
 	dnsManager *DNSManager
@@ -34,14 +51,45 @@ type connectionManagerConfig struct {
 	dialFunc DialFunc
 	// dialOptions 保存创建连接时的固定拨号选项。
 	dialOptions []grpc.DialOption
+	// idleTimeout 保存空闲淘汰时长。
+	idleTimeout time.Duration
+	// maxAge 保存连接最长使用时长。
+	maxAge time.Duration
+	// drainTimeout 保存旧连接延迟关闭时长。
+	drainTimeout time.Duration
+}
+
+// sweepInterval 返回后台淘汰巡检周期；未开启淘汰与轮换时返回 0。
+func (c *connectionManagerConfig) sweepInterval() time.Duration {
+	var interval time.Duration
+	for _, d := range []time.Duration{c.idleTimeout, c.maxAge} {
+		if d > 0 && (interval == 0 || d < interval) {
+			interval = d
+		}
+	}
+	if interval == 0 {
+		return 0
+	}
+	// 以最短时长的一半巡检，保证淘汰延迟不超过配置值的 1.5 倍。
+	interval /= 2
+	if interval < minSweepInterval {
+		interval = minSweepInterval
+	}
+	return interval
 }
 
 // normalize 补齐 ConnectionManagerOptions 的默认值。
 func (o ConnectionManagerOptions) normalize() *connectionManagerConfig {
 	// 先构造内部配置对象，后续统一在这份对象上补齐默认值。
 	config := &connectionManagerConfig{
-		dnsManager: o.DNSManager,
-		dialFunc:   o.DialFunc,
+		dnsManager:   o.DNSManager,
+		dialFunc:     o.DialFunc,
+		idleTimeout:  o.IdleTimeout,
+		maxAge:       o.MaxAge,
+		drainTimeout: o.DrainTimeout,
+	}
+	if config.drainTimeout <= 0 {
+		config.drainTimeout = DefaultDrainTimeout
 	}
 	// 若未显式提供 DNS 管理器，则使用一份默认配置。
 	if config.dnsManager == nil {
@@ -52,10 +100,7 @@ func (o ConnectionManagerOptions) normalize() *connectionManagerConfig {
 	}
 	if len(o.DialOptions) == 0 {
 		// 默认注入基础凭据与 OTel client handler。
-		config.dialOptions = []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-		}
+		config.dialOptions = DefaultDialOptions()
 		// 默认选项场景到这里即可返回。
 		return config
 	}
@@ -64,6 +109,33 @@ func (o ConnectionManagerOptions) normalize() *connectionManagerConfig {
 	return config
 }
 
+// connEntry 表示一条缓存连接及其使用时间。
+type connEntry struct {
+	// conn 表示缓存的连接。
+	conn *grpc.ClientConn
+	// createdAt 表示连接创建时间，用于 MaxAge 轮换。
+	createdAt time.Time
+	// lastUsed 表示最近一次被 Dial 取用的 UnixNano 时间，用于空闲淘汰。
+	lastUsed atomic.Int64
+}
+
+// retiredConn 表示已被轮换、等待延迟关闭的旧连接。
+type retiredConn struct {
+	conn      *grpc.ClientConn
+	retiredAt time.Time
+}
+
+// DefaultDialOptions 返回未显式配置 DialOptions 时使用的默认拨号选项：
+// insecure 凭据与 otelgrpc client handler。
+//
+// 需要在默认选项基础上追加拦截器等配置时，可以先取这份默认值再追加。
+func DefaultDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+}
+
 // ConnectionManager 负责缓存基于 DNS 创建出的 grpc.ClientConn。
 //
 // 它把“业务服务 DNS -> 目标解析 -> 连接缓存”统一收敛在一处，
@@ -71,17 +143,24 @@ func (o ConnectionManagerOptions) normalize() *connectionManagerConfig {
 // - target 拼装；
 // - resolver scheme；
 // - 拨号选项；
-// - 多次调用的连接复用。
+// - 多次调用的连接复用；
+// - 空闲连接淘汰与到期轮换。
 type ConnectionManager struct {
-	// mu 保护 conns 与 closed，避免并发 Dial/Close 时出现竞态。
+	// mu 保护 conns、retired 与 closed，避免并发 Dial/Close 时出现竞态。
 	mu sync.RWMutex
 
 	// options 保存连接管理器初始化后的规范化配置。
 	config *connectionManagerConfig
 	// conns 按最终 gRPC target 缓存可复用连接。
-	conns map[string]*grpc.ClientConn
+	conns map[string]*connEntry
+	// retired 保存已被轮换、等待在途请求结束后关闭的旧连接。
+	retired []retiredConn
 	// closed 标记当前管理器是否已经关闭。
 	closed bool
+	// stop 用于通知后台巡检协程退出；未开启淘汰时为 nil。
+	stop chan struct{}
+	// now 返回当前时间，测试可替换。
+	now func() time.Time
 }
 
 // NewConnectionManager 创建连接管理器。
@@ -95,13 +174,24 @@ func NewConnectionManager(options ConnectionManagerOptions) (*ConnectionManager,
 	if config.dialFunc == nil {
 		return nil, ErrDialFnIsNil
 	}
+	// 直连地址覆盖配置错误时在启动阶段失败。
+	if err := config.dnsManager.Validate(); err != nil {
+		return nil, err
+	}
 
-	return &ConnectionManager{
+	manager := &ConnectionManager{
 		// 保存归一化后的内部配置。
 		config: config,
 		// 初始化连接缓存 map。
-		conns: make(map[string]*grpc.ClientConn),
-	}, nil
+		conns: make(map[string]*connEntry),
+		now:   time.Now,
+	}
+	// 只有开启空闲淘汰或到期轮换时才启动后台巡检。
+	if interval := config.sweepInterval(); interval > 0 {
+		manager.stop = make(chan struct{})
+		go manager.runSweeper(interval)
+	}
+	return manager, nil
 }
 
 // DefaultDialFunc 是默认的 grpc.ClientConn 创建逻辑。
@@ -126,9 +216,8 @@ func DefaultDialFunc(_ context.Context, target Target, options []grpc.DialOption
 // - 逻辑上等价的服务身份只会生成一条连接；
 // - 端口覆盖、cluster domain、resolver scheme 的变化都能体现在缓存键上。
 func (m *ConnectionManager) Dial(ctx context.Context, dns *DNS) (*grpc.ClientConn, error) {
-	// 先通过 DNS 管理器把业务服务描述转换成稳定目标。
-	// 通过 DNS 管理器把业务服务配置转成最终目标。
-	target, err := m.config.dnsManager.Build(dns)
+	// 先把业务服务描述转换成稳定目标；单次调用直连地址优先于 DNS。
+	target, err := m.resolveTarget(ctx, dns)
 	if err != nil {
 		return nil, err
 	}
@@ -136,15 +225,17 @@ func (m *ConnectionManager) Dial(ctx context.Context, dns *DNS) (*grpc.ClientCon
 	// 缓存键统一使用最终 gRPC target，保证语义等价请求命中同一连接。
 	key := target.GRPCTarget()
 	// 第一阶段走读锁快路径，尽量减少命中缓存时的锁竞争。
+	now := m.now()
 	m.mu.RLock()
 	if m.closed {
 		m.mu.RUnlock()
 		return nil, ErrConnectionManagerClosed
 	}
-	if conn, ok := m.conns[key]; ok {
+	if entry, ok := m.conns[key]; ok && !m.expired(entry, now) {
 		// 命中缓存时直接复用已有连接，避免重复拨号。
+		entry.lastUsed.Store(now.UnixNano())
 		m.mu.RUnlock()
-		return conn, nil
+		return entry.conn, nil
 	}
 	m.mu.RUnlock()
 
@@ -154,10 +245,11 @@ func (m *ConnectionManager) Dial(ctx context.Context, dns *DNS) (*grpc.ClientCon
 		m.mu.Unlock()
 		return nil, ErrConnectionManagerClosed
 	}
-	if conn, ok := m.conns[key]; ok {
+	if entry, ok := m.conns[key]; ok && !m.expired(entry, now) {
 		// 双检一次，避免多个并发协程同时进入慢路径时重复建连。
+		entry.lastUsed.Store(now.UnixNano())
 		m.mu.Unlock()
-		return conn, nil
+		return entry.conn, nil
 	}
 	// 在锁内只读取固定配置，避免把真实拨号过程放在锁内阻塞其它协程。
 	dialFunc := m.config.dialFunc
@@ -178,17 +270,91 @@ func (m *ConnectionManager) Dial(ctx context.Context, dns *DNS) (*grpc.ClientCon
 		_ = conn.Close()
 		return nil, ErrConnectionManagerClosed
 	}
+	now = m.now()
 	if cached, ok := m.conns[key]; ok {
-		// 若别的协程已经抢先写入缓存，则关闭当前新连接并复用已有连接。
-		_ = conn.Close()
-		return cached, nil
+		if !m.expired(cached, now) {
+			// 若别的协程已经抢先写入缓存，则关闭当前新连接并复用已有连接。
+			_ = conn.Close()
+			cached.lastUsed.Store(now.UnixNano())
+			return cached.conn, nil
+		}
+		// 到期连接可能仍有在途请求，先移入待关闭列表，由巡检在 DrainTimeout 后关闭。
+		m.retired = append(m.retired, retiredConn{conn: cached.conn, retiredAt: now})
 	}
 	// 仅在拨号成功后写入缓存，避免缓存无效连接。
-	m.conns[key] = conn
+	entry := &connEntry{conn: conn, createdAt: now}
+	entry.lastUsed.Store(now.UnixNano())
+	m.conns[key] = entry
 	// 返回缓存中的新连接。
 	return conn, nil
 }
 
+// resolveTarget 解析拨号目标：ctx 携带 WithAddressOverride 时直连该地址，否则通过 DNS 管理器构造。
+func (m *ConnectionManager) resolveTarget(ctx context.Context, dns *DNS) (*Target, error) {
+	if address, ok := addressOverrideFromContext(ctx); ok {
+		return parseOverrideTarget(address)
+	}
+	return m.config.dnsManager.Build(dns)
+}
+
+// expired 判断缓存连接是否已超过 MaxAge。
+func (m *ConnectionManager) expired(entry *connEntry, now time.Time) bool {
+	return m.config.maxAge > 0 && now.Sub(entry.createdAt) >= m.config.maxAge
+}
+
+// runSweeper 周期执行空闲淘汰与旧连接关闭，直到管理器关闭。
+func (m *ConnectionManager) runSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.sweep(m.now())
+		}
+	}
+}
+
+// sweep 把空闲超时的缓存连接移出缓存，并关闭排空期已过的旧连接。
+//
+// 空闲淘汰与 MaxAge 轮换一样先进入待关闭列表，在 DrainTimeout 后才关闭：
+// 最后一次 Dial 早于 IdleTimeout 的连接上仍可能有在途请求。
+// 真正的 Close 在锁外执行，避免关闭过程阻塞 Dial 热路径。
+func (m *ConnectionManager) sweep(now time.Time) {
+	var closing []*grpc.ClientConn
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	if m.config.idleTimeout > 0 {
+		for key, entry := range m.conns {
+			if now.Sub(time.Unix(0, entry.lastUsed.Load())) >= m.config.idleTimeout {
+				m.retired = append(m.retired, retiredConn{conn: entry.conn, retiredAt: now})
+				delete(m.conns, key)
+			}
+		}
+	}
+	remaining := m.retired[:0]
+	for _, retired := range m.retired {
+		if now.Sub(retired.retiredAt) >= m.config.drainTimeout {
+			closing = append(closing, retired.conn)
+			continue
+		}
+		remaining = append(remaining, retired)
+	}
+	m.retired = remaining
+	m.mu.Unlock()
+
+	for _, conn := range closing {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}
+}
+
 // Close 关闭连接管理器及其持有的全部 grpc.ClientConn。
 //
 // Close 会尽最大努力关闭所有连接；
@@ -202,18 +368,31 @@ func (m *ConnectionManager) Close() error {
 	}
 
 	m.closed = true
+	if m.stop != nil {
+		close(m.stop)
+	}
 
 	var firstErr error
-	for key, conn := range m.conns {
-		if conn == nil {
+	for key, entry := range m.conns {
+		if entry == nil || entry.conn == nil {
 			delete(m.conns, key)
 			continue
 		}
-		if err := conn.Close(); err != nil && firstErr == nil {
+		if err := entry.conn.Close(); err != nil && firstErr == nil {
 			firstErr = err
 		}
 		delete(m.conns, key)
 	}
+	// 停机时不再等待排空期，直接关闭已轮换的旧连接。
+	for _, retired := range m.retired {
+		if retired.conn == nil {
+			continue
+		}
+		if err := retired.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	m.retired = nil
 
 	return firstErr
 }
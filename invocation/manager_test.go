@@ -5,8 +5,10 @@ import (
 	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -133,3 +135,124 @@ func TestDefaultDialFunc_RejectsInvalidTarget(t *testing.T) {
 		t.Fatal("expected error for invalid target")
 	}
 }
+
+func TestConnectionManager_EvictsIdleConnections(t *testing.T) {
+	var dialCount atomic.Int32
+	manager, err := NewConnectionManager(ConnectionManagerOptions{
+		IdleTimeout: time.Minute,
+		DialFunc: func(ctx context.Context, target Target, options []grpc.DialOption) (*grpc.ClientConn, error) {
+			dialCount.Add(1)
+			return grpc.NewClient("passthrough:///auth.default.svc.cluster.local:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer func() { _ = manager.Close() }()
+
+	now := time.Unix(1710000000, 0)
+	manager.now = func() time.Time { return now }
+	service := &DNS{Service: "auth", Namespace: "default"}
+
+	conn, err := manager.Dial(context.Background(), service)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// 未超过空闲时长的连接保留在缓存中。
+	manager.sweep(now.Add(30 * time.Second))
+	if len(manager.conns) != 1 {
+		t.Fatalf("expected connection to be kept, got %d", len(manager.conns))
+	}
+
+	// 超过空闲时长后连接移出缓存，但在排空期内保持可用，留给仍在途的请求结束。
+	manager.sweep(now.Add(2 * time.Minute))
+	if len(manager.conns) != 0 {
+		t.Fatalf("expected idle connection to be evicted, got %d", len(manager.conns))
+	}
+	if conn.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected evicted connection to stay open during drain")
+	}
+	manager.sweep(now.Add(2*time.Minute + DefaultDrainTimeout))
+	if conn.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected evicted connection to be closed after drain, got %s", conn.GetState())
+	}
+
+	// 下一次 Dial 重新拨号。
+	if _, err := manager.Dial(context.Background(), service); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if dialCount.Load() != 2 {
+		t.Fatalf("expected dial count 2, got %d", dialCount.Load())
+	}
+}
+
+func TestConnectionManager_RotatesConnectionsAfterMaxAge(t *testing.T) {
+	manager, err := NewConnectionManager(ConnectionManagerOptions{
+		MaxAge:       time.Hour,
+		DrainTimeout: time.Minute,
+		DialFunc: func(ctx context.Context, target Target, options []grpc.DialOption) (*grpc.ClientConn, error) {
+			return grpc.NewClient("passthrough:///auth.default.svc.cluster.local:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer func() { _ = manager.Close() }()
+
+	now := time.Unix(1710000000, 0)
+	manager.now = func() time.Time { return now }
+	service := &DNS{Service: "auth", Namespace: "default"}
+
+	oldConn, _ := manager.Dial(context.Background(), service)
+	now = now.Add(2 * time.Hour)
+	newConn, err := manager.Dial(context.Background(), service)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if newConn == oldConn {
+		t.Fatalf("expected expired connection to be rotated")
+	}
+
+	// 旧连接在排空期内保持可用，排空期过后才关闭。
+	manager.sweep(now.Add(30 * time.Second))
+	if oldConn.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected retired connection to stay open during drain")
+	}
+	manager.sweep(now.Add(2 * time.Minute))
+	if oldConn.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected retired connection to be closed after drain, got %s", oldConn.GetState())
+	}
+	if newConn.GetState() == connectivity.Shutdown {
+		t.Fatalf("expected current connection to stay open")
+	}
+}
+
+func TestConnectionManager_Dial_UsesContextAddressOverride(t *testing.T) {
+	var dialed []string
+	manager, err := NewConnectionManager(ConnectionManagerOptions{
+		DialFunc: func(ctx context.Context, target Target, options []grpc.DialOption) (*grpc.ClientConn, error) {
+			dialed = append(dialed, target.GRPCTarget())
+			return grpc.NewClient(target.GRPCTarget(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	defer func() { _ = manager.Close() }()
+
+	ctx := WithAddressOverride(context.Background(), "127.0.0.1:19090")
+	if _, err := manager.Dial(ctx, &DNS{Service: "auth"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if _, err := manager.Dial(context.Background(), &DNS{Service: "auth"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(dialed) != 2 || dialed[0] != "passthrough:///127.0.0.1:19090" || dialed[1] != "dns:///auth.default.svc.cluster.local:9090" {
+		t.Fatalf("unexpected dialed targets: %v", dialed)
+	}
+
+	if _, err := manager.Dial(WithAddressOverride(context.Background(), "bad"), &DNS{Service: "auth"}); !errors.Is(err, ErrAddressOverrideInvalid) {
+		t.Fatalf("expected ErrAddressOverrideInvalid, got %v", err)
+	}
+}
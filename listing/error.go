@@ -0,0 +1,23 @@
+// Package listing 提供列表类 RPC 统一的分页、过滤与排序解析工具。
+package listing
+
+import "errors"
+
+var (
+	// ErrSecretEmpty 表示没有提供分页令牌签名密钥。
+	ErrSecretEmpty = errors.New("listing page token secret is empty")
+	// ErrPageSizeInvalid 表示分页大小为负数。
+	ErrPageSizeInvalid = errors.New("listing page size is invalid")
+	// ErrPageTokenInvalid 表示分页令牌格式错误或签名校验失败。
+	ErrPageTokenInvalid = errors.New("listing page token is invalid")
+	// ErrPageTokenMismatch 表示分页令牌与当前查询条件不一致，不能跨查询复用。
+	ErrPageTokenMismatch = errors.New("listing page token does not match query")
+	// ErrFilterInvalid 表示过滤表达式语法错误。
+	ErrFilterInvalid = errors.New("listing filter is invalid")
+	// ErrFilterFieldNotAllowed 表示过滤字段不在白名单内。
+	ErrFilterFieldNotAllowed = errors.New("listing filter field is not allowed")
+	// ErrOrderByInvalid 表示排序表达式语法错误。
+	ErrOrderByInvalid = errors.New("listing order by is invalid")
+	// ErrOrderFieldNotAllowed 表示排序字段不在白名单内。
+	ErrOrderFieldNotAllowed = errors.New("listing order field is not allowed")
+)
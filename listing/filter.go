@@ -0,0 +1,164 @@
+package listing
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Operator 表示过滤条件的比较运算符。
+type Operator string
+
+const (
+	OperatorEqual        Operator = "="
+	OperatorNotEqual     Operator = "!="
+	OperatorGreater      Operator = ">"
+	OperatorGreaterEqual Operator = ">="
+	OperatorLess         Operator = "<"
+	OperatorLessEqual    Operator = "<="
+	// OperatorHas 表示包含匹配，字符串字段为子串匹配，列表字段为元素匹配，由业务自行解释。
+	OperatorHas Operator = ":"
+)
+
+// Condition 表示一条过滤条件。
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+// ParseFilter 解析过滤表达式。
+//
+// 语法为以 AND 连接的 `field op value`，例如：
+//
+//	status = "ACTIVE" AND created_at >= "2024-01-01" AND name : foo
+//
+// allowed 为空时不做字段白名单校验；value 含空白时必须使用双引号，引号内可用 \" 转义。
+func ParseFilter(expr string, allowed []string) ([]Condition, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	conditions := make([]Condition, 0, (len(tokens)+1)/4)
+	for i := 0; i < len(tokens); {
+		// 每条条件固定为 field op value 三个 token。
+		if i+3 > len(tokens) || tokens[i].quoted || !tokens[i+1].operator {
+			return nil, fmt.Errorf("%w: %q", ErrFilterInvalid, expr)
+		}
+		field := tokens[i].text
+		if !isIdentifier(field) {
+			return nil, fmt.Errorf("%w: bad field %q", ErrFilterInvalid, field)
+		}
+		if len(allowed) != 0 && !contains(allowed, field) {
+			return nil, fmt.Errorf("%w: %s", ErrFilterFieldNotAllowed, field)
+		}
+		if tokens[i+2].operator {
+			return nil, fmt.Errorf("%w: %q", ErrFilterInvalid, expr)
+		}
+		conditions = append(conditions, Condition{
+			Field:    field,
+			Operator: Operator(tokens[i+1].text),
+			Value:    tokens[i+2].text,
+		})
+		i += 3
+
+		// 条件之间必须以 AND 连接。
+		if i < len(tokens) {
+			if tokens[i].quoted || !strings.EqualFold(tokens[i].text, "AND") || i+1 == len(tokens) {
+				return nil, fmt.Errorf("%w: %q", ErrFilterInvalid, expr)
+			}
+			i++
+		}
+	}
+	return conditions, nil
+}
+
+// filterToken 表示过滤表达式中的一个词法单元。
+type filterToken struct {
+	text     string
+	quoted   bool
+	operator bool
+}
+
+// tokenizeFilter 把过滤表达式切分为词法单元。
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			// 双引号字符串，支持 \" 与 \\ 转义。
+			var builder strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					builder.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("%w: unterminated string", ErrFilterInvalid)
+			}
+			tokens = append(tokens, filterToken{text: builder.String(), quoted: true})
+		case strings.ContainsRune("=!<>:", r):
+			// 运算符优先匹配两个字符的形式。
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("%w: unexpected '!'", ErrFilterInvalid)
+			}
+			tokens = append(tokens, filterToken{text: op, operator: true})
+			i += len(op)
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("=!<>:\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+// isIdentifier 判断字段名是否为合法标识符，支持 a.b 形式的嵌套字段。
+func isIdentifier(field string) bool {
+	if field == "" {
+		return false
+	}
+	for index, r := range field {
+		if r == '_' || r == '.' || unicode.IsLetter(r) || (index > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// contains 判断切片中是否包含指定字符串。
+func contains(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
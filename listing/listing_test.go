@@ -0,0 +1,118 @@
+package listing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	conditions, err := ParseFilter(`status = "ACTIVE" and created_at >= 2024-01-01 AND name : "foo \"bar\""`, []string{"status", "created_at", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Condition{
+		{Field: "status", Operator: OperatorEqual, Value: "ACTIVE"},
+		{Field: "created_at", Operator: OperatorGreaterEqual, Value: "2024-01-01"},
+		{Field: "name", Operator: OperatorHas, Value: `foo "bar"`},
+	}
+	if len(conditions) != len(expected) {
+		t.Fatalf("unexpected conditions: %+v", conditions)
+	}
+	for i := range expected {
+		if conditions[i] != expected[i] {
+			t.Fatalf("unexpected condition %d: %+v", i, conditions[i])
+		}
+	}
+}
+
+func TestParseFilterRejectsInvalidExpressions(t *testing.T) {
+	cases := map[string]error{
+		`status = `:                 ErrFilterInvalid,
+		`status = a OR name = b`:    ErrFilterInvalid,
+		`status == a`:               ErrFilterInvalid,
+		`name = "unterminated`:      ErrFilterInvalid,
+		`status = a AND`:            ErrFilterInvalid,
+		`secret = a`:                ErrFilterFieldNotAllowed,
+		`"status" = a`:              ErrFilterInvalid,
+		`status != a AND 1bad = b`:  ErrFilterInvalid,
+		`status ! a`:                ErrFilterInvalid,
+		`status = a name = b`:       ErrFilterInvalid,
+		`status = a AND name = b c`: ErrFilterInvalid,
+	}
+	for expr, expected := range cases {
+		if _, err := ParseFilter(expr, []string{"status", "name"}); !errors.Is(err, expected) {
+			t.Fatalf("filter %q: expected %v, got %v", expr, expected, err)
+		}
+	}
+}
+
+func TestParseOrderBy(t *testing.T) {
+	orders, err := ParseOrderBy("create_time DESC, name", []string{"create_time", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 2 || orders[0] != (Order{Field: "create_time", Desc: true}) || orders[1] != (Order{Field: "name"}) {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+
+	if _, err := ParseOrderBy("password", []string{"name"}); !errors.Is(err, ErrOrderFieldNotAllowed) {
+		t.Fatalf("expected ErrOrderFieldNotAllowed, got %v", err)
+	}
+	if _, err := ParseOrderBy("name sideways", nil); !errors.Is(err, ErrOrderByInvalid) {
+		t.Fatalf("expected ErrOrderByInvalid, got %v", err)
+	}
+	if _, err := ParseOrderBy("name, name desc", nil); !errors.Is(err, ErrOrderByInvalid) {
+		t.Fatalf("expected duplicate field to be rejected, got %v", err)
+	}
+}
+
+func TestParserPageTokenRoundTrip(t *testing.T) {
+	parser, err := NewParser(Options{Secret: []byte("secret"), MaxPageSize: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := parser.Parse(Request{PageSize: 500, Filter: "status = ACTIVE", OrderBy: "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.PageSize != 50 {
+		t.Fatalf("expected page size to be capped, got %d", first.PageSize)
+	}
+
+	token, err := parser.NextPageToken(first, "offset:50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 规范化后等价的查询可以继续使用令牌。
+	second, err := parser.Parse(Request{PageToken: token, Filter: `status   =  "ACTIVE"`, OrderBy: "name asc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Cursor != "offset:50" || second.PageSize != DefaultPageSize {
+		t.Fatalf("unexpected query: %+v", second)
+	}
+
+	if _, err := parser.Parse(Request{PageToken: token, Filter: "status = DELETED", OrderBy: "name"}); !errors.Is(err, ErrPageTokenMismatch) {
+		t.Fatalf("expected ErrPageTokenMismatch, got %v", err)
+	}
+	if _, err := parser.Parse(Request{PageToken: token + "x", Filter: "status = ACTIVE", OrderBy: "name"}); !errors.Is(err, ErrPageTokenInvalid) {
+		t.Fatalf("expected ErrPageTokenInvalid, got %v", err)
+	}
+	if _, err := parser.Parse(Request{PageSize: -1}); !errors.Is(err, ErrPageSizeInvalid) {
+		t.Fatalf("expected ErrPageSizeInvalid, got %v", err)
+	}
+
+	other, _ := NewParser(Options{Secret: []byte("other")})
+	if _, err := other.Parse(Request{PageToken: token, Filter: "status = ACTIVE", OrderBy: "name"}); !errors.Is(err, ErrPageTokenInvalid) {
+		t.Fatalf("expected token signed by another secret to be rejected, got %v", err)
+	}
+}
+
+func TestNewParserRequiresSecret(t *testing.T) {
+	if _, err := NewParser(Options{}); !errors.Is(err, ErrSecretEmpty) {
+		t.Fatalf("expected ErrSecretEmpty, got %v", err)
+	}
+}
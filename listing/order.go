@@ -0,0 +1,53 @@
+package listing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Order 表示一条排序规则。
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOrderBy 解析排序表达式。
+//
+// 语法为逗号分隔的 `field [asc|desc]`，例如 `create_time desc, name`；
+// allowed 为空时不做字段白名单校验，同一字段重复出现视为语法错误。
+func ParseOrderBy(expr string, allowed []string) ([]Order, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(expr, ",")
+	orders := make([]Order, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 || !isIdentifier(fields[0]) {
+			return nil, fmt.Errorf("%w: %q", ErrOrderByInvalid, expr)
+		}
+
+		order := Order{Field: fields[0]}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				order.Desc = true
+			default:
+				return nil, fmt.Errorf("%w: %q", ErrOrderByInvalid, expr)
+			}
+		}
+
+		if len(allowed) != 0 && !contains(allowed, order.Field) {
+			return nil, fmt.Errorf("%w: %s", ErrOrderFieldNotAllowed, order.Field)
+		}
+		if _, ok := seen[order.Field]; ok {
+			return nil, fmt.Errorf("%w: duplicate field %s", ErrOrderByInvalid, order.Field)
+		}
+		seen[order.Field] = struct{}{}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
@@ -0,0 +1,149 @@
+package listing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultPageSize 是未指定分页大小时使用的默认值。
+	DefaultPageSize = 20
+	// DefaultMaxPageSize 是分页大小的默认上限。
+	DefaultMaxPageSize = 100
+)
+
+// Options 定义列表解析器配置。
+type Options struct {
+	// Secret 表示分页令牌 HMAC 签名密钥，同一服务的所有副本及网关必须一致。
+	Secret []byte
+	// DefaultPageSize 表示未指定分页大小时的默认值；未设置时使用 DefaultPageSize。
+	DefaultPageSize int32
+	// MaxPageSize 表示分页大小上限，超出时截断；未设置时使用 DefaultMaxPageSize。
+	MaxPageSize int32
+	// FilterFields 表示允许过滤的字段白名单；为空时不限制。
+	FilterFields []string
+	// OrderFields 表示允许排序的字段白名单；为空时不限制。
+	OrderFields []string
+}
+
+// normalize 补齐默认值。
+func (o Options) normalize() Options {
+	if o.DefaultPageSize <= 0 {
+		o.DefaultPageSize = DefaultPageSize
+	}
+	if o.MaxPageSize <= 0 {
+		o.MaxPageSize = DefaultMaxPageSize
+	}
+	if o.DefaultPageSize > o.MaxPageSize {
+		o.DefaultPageSize = o.MaxPageSize
+	}
+	return o
+}
+
+// Request 表示列表 RPC 的通用分页参数，字段语义对齐 AIP-132。
+type Request struct {
+	PageSize  int32
+	PageToken string
+	Filter    string
+	OrderBy   string
+}
+
+// Query 表示解析校验后的列表查询。
+type Query struct {
+	// PageSize 表示本页实际大小，已经应用默认值与上限。
+	PageSize int32
+	// Cursor 表示本页起始游标，第一页为空。
+	Cursor     string
+	Conditions []Condition
+	Orders     []Order
+	// hash 绑定过滤与排序条件，用于生成下一页令牌。
+	hash string
+}
+
+// Parser 负责统一解析与校验列表请求。
+type Parser struct {
+	options Options
+	codec   *TokenCodec
+}
+
+// NewParser 创建列表解析器。
+func NewParser(options Options) (*Parser, error) {
+	options = options.normalize()
+	codec, err := NewTokenCodec(options.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{options: options, codec: codec}, nil
+}
+
+// Parse 解析并校验列表请求，网关与服务端可使用相同配置做一致校验。
+func (p *Parser) Parse(request Request) (*Query, error) {
+	pageSize, err := p.pageSize(request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	conditions, err := ParseFilter(request.Filter, p.options.FilterFields)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := ParseOrderBy(request.OrderBy, p.options.OrderFields)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := queryHash(conditions, orders)
+	token, err := p.codec.Decode(request.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	// 翻页过程中修改过滤或排序条件会导致游标语义错乱，必须重新从第一页开始。
+	if request.PageToken != "" && token.QueryHash != hash {
+		return nil, ErrPageTokenMismatch
+	}
+
+	return &Query{
+		PageSize:   pageSize,
+		Cursor:     token.Cursor,
+		Conditions: conditions,
+		Orders:     orders,
+		hash:       hash,
+	}, nil
+}
+
+// NextPageToken 基于下一页游标生成分页令牌；cursor 为空表示没有下一页，返回空字符串。
+func (p *Parser) NextPageToken(query *Query, cursor string) (string, error) {
+	if query == nil || cursor == "" {
+		return "", nil
+	}
+	return p.codec.Encode(PageToken{Cursor: cursor, QueryHash: query.hash})
+}
+
+// pageSize 应用默认值与上限。
+func (p *Parser) pageSize(size int32) (int32, error) {
+	switch {
+	case size < 0:
+		return 0, fmt.Errorf("%w: %d", ErrPageSizeInvalid, size)
+	case size == 0:
+		return p.options.DefaultPageSize, nil
+	case size > p.options.MaxPageSize:
+		return p.options.MaxPageSize, nil
+	default:
+		return size, nil
+	}
+}
+
+// queryHash 基于规范化后的过滤与排序条件计算摘要，忽略空白与大小写差异。
+func queryHash(conditions []Condition, orders []Order) string {
+	var builder strings.Builder
+	for _, condition := range conditions {
+		fmt.Fprintf(&builder, "%s\x00%s\x00%s\x01", condition.Field, condition.Operator, condition.Value)
+	}
+	builder.WriteByte('\x02')
+	for _, order := range orders {
+		fmt.Fprintf(&builder, "%s\x00%t\x01", order.Field, order.Desc)
+	}
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// annotationsContextKey 是请求级注解容器在 ctx 中的 key。
+type annotationsContextKey struct{}
+
+// annotations 保存单个请求内业务 handler 追加的领域标识字段。
+type annotations struct {
+	mu sync.Mutex
+	// fields 按首次写入顺序保存注解，同名 key 后写覆盖先写。
+	fields []zap.Field
+}
+
+// WithAnnotations 为当前请求安装注解容器，通常由访问日志中间件在请求入口调用。
+//
+// ctx 中已经存在容器时直接返回原 ctx，保证嵌套中间件共享同一份注解。
+func WithAnnotations(ctx context.Context) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	if _, ok := ctx.Value(annotationsContextKey{}).(*annotations); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, annotationsContextKey{}, &annotations{})
+}
+
+// Annotate 为当前请求追加领域标识，例如 order_id、payment_id。
+//
+// 注解会自动出现在同一请求后续输出的访问日志和服务日志中；
+// ctx 中没有注解容器（入口未挂载访问日志中间件）时返回 false。
+func Annotate(ctx context.Context, key string, value any) bool {
+	if ctx == nil || key == "" {
+		return false
+	}
+	holder, ok := ctx.Value(annotationsContextKey{}).(*annotations)
+	if !ok {
+		return false
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	field := zap.Any(key, value)
+	for index := range holder.fields {
+		if holder.fields[index].Key == key {
+			holder.fields[index] = field
+			return true
+		}
+	}
+	holder.fields = append(holder.fields, field)
+	return true
+}
+
+// AnnotationFields 返回当前请求注解的快照；没有注解时返回 nil。
+func AnnotationFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	holder, ok := ctx.Value(annotationsContextKey{}).(*annotations)
+	if !ok {
+		return nil
+	}
+
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if len(holder.fields) == 0 {
+		return nil
+	}
+	return append([]zap.Field(nil), holder.fields...)
+}
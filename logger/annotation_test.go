@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestAnnotateAppearsInServerLog 验证注解会自动写入同一请求内的服务日志。
+func TestAnnotateAppearsInServerLog(t *testing.T) {
+	// 用 observer 构造一个可观测的内存 core。
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	log := NewServerLogger(zap.New(baseCore))
+
+	// 模拟入口中间件安装注解容器，handler 追加领域标识。
+	ctx := WithAnnotations(context.Background())
+	if !Annotate(ctx, "order_id", "o-1") {
+		t.Fatal("expected annotate to succeed")
+	}
+	// 同名 key 后写覆盖先写。
+	Annotate(ctx, "order_id", "o-2")
+	Annotate(ctx, "payment_id", "p-1")
+	// 调用方显式传入的同名字段优先于注解。
+	Annotate(ctx, "k", "annotation")
+
+	log.WithContextError(ctx, "charge failed", zap.String("k", "explicit"))
+
+	fields := observed.All()[0].ContextMap()
+	if fields["order_id"] != "o-2" || fields["payment_id"] != "p-1" {
+		t.Fatalf("unexpected annotation fields: %v", fields)
+	}
+	if fields["k"] != "explicit" {
+		t.Fatalf("expected explicit field to win, got %v", fields["k"])
+	}
+}
+
+// TestAnnotateWithoutContainer 验证未安装容器时注解被安全忽略。
+func TestAnnotateWithoutContainer(t *testing.T) {
+	ctx := context.Background()
+	if Annotate(ctx, "order_id", "o-1") {
+		t.Fatal("expected annotate to report missing container")
+	}
+	if fields := AnnotationFields(ctx); fields != nil {
+		t.Fatalf("expected no annotation fields, got %v", fields)
+	}
+
+	// 重复安装容器应复用同一份注解。
+	installed := WithAnnotations(ctx)
+	if WithAnnotations(installed) != installed {
+		t.Fatal("expected nested install to reuse existing container")
+	}
+}
@@ -3,4 +3,7 @@ package logger
 type Config struct {
 	Console bool `json:"console"`
 	Remote  bool `json:"remote"`
+	// Buffered 为 true 时 console 输出先写入内存缓冲并批量刷新，请求路径不再同步等待 stdout 写入；
+	// 进程退出前需调用 zap.Logger.Sync 刷出剩余日志。
+	Buffered bool `json:"buffered"`
 }
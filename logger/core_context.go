@@ -36,6 +36,13 @@ func appendContextFields(ctx context.Context, logType string, fields []zap.Field
 		}
 	}
 
+	// 追加业务 handler 通过 Annotate 写入的领域标识，调用方显式传入的同名字段优先。
+	for _, field := range AnnotationFields(ctx) {
+		if !hasField(result, field.Key) {
+			result = append(result, field)
+		}
+	}
+
 	// 原始 ctx 仍然保留在字段里，供 otelzap core 在发 OTEL log record 时取用。
 	// 普通 console core 会在外层被过滤掉这个字段，避免把整棵 context 树打印出来。
 	result = append(result, zap.Any("ctx", ctx))
@@ -17,7 +17,11 @@ func NewZapLogger(config *Config) *zap.Logger {
 	cores := make([]zapcore.Core, 0, 2)
 	// 开启 console 时，给普通输出 core 套一层 ctx 过滤包装。
 	if config.Console {
-		cores = append(cores, NewContextOmittingCore(NewConsoleCore(atomicLevel)))
+		console := NewConsoleCore(atomicLevel)
+		if config.Buffered {
+			console = NewBufferedConsoleCore(atomicLevel)
+		}
+		cores = append(cores, NewContextOmittingCore(console))
 	}
 	// 开启 remote 时，直接挂上 otelzap core。
 	if config.Remote {
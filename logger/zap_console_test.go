@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBufferedConsoleCoreFlushesOnSync(t *testing.T) {
+	var out bytes.Buffer
+	core := newConsoleCore(zapcore.InfoLevel, &zapcore.BufferedWriteSyncer{
+		WS:            zapcore.AddSync(&out),
+		FlushInterval: time.Hour,
+	})
+	log := zap.New(core)
+
+	log.Info("buffered entry")
+	if out.Len() != 0 {
+		t.Fatalf("expected entry to stay buffered, got %q", out.String())
+	}
+	if err := log.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !strings.Contains(out.String(), "buffered entry") {
+		t.Fatalf("expected entry after sync, got %q", out.String())
+	}
+}
@@ -0,0 +1,166 @@
+package gm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultAuditWritePrefixes 是按命名约定识别写操作的方法名前缀。
+var DefaultAuditWritePrefixes = []string{"Create", "Update", "Delete", "Remove", "Set", "Add", "Put", "Patch", "Batch", "Import", "Grant", "Revoke"}
+
+// AuditOptions 定义操作审计拦截器配置。
+type AuditOptions struct {
+	// WritePrefixes 表示识别写操作的方法短名前缀；为空时使用 DefaultAuditWritePrefixes。
+	WritePrefixes []string
+	// Methods 表示显式标注需要审计的完整方法名，不受命名约定限制。
+	Methods []string
+	// SkipMethods 表示显式排除审计的完整方法名，优先于 Methods 与命名约定。
+	SkipMethods []string
+	// MaxPayloadBytes 表示 request/before/after 报文写入的最大字节数；<= 0 表示不限制。
+	MaxPayloadBytes int
+}
+
+func (o AuditOptions) normalize() AuditOptions {
+	if len(o.WritePrefixes) == 0 {
+		o.WritePrefixes = DefaultAuditWritePrefixes
+	}
+	return o
+}
+
+// auditChange 保存 handler 记录的变更前后快照。
+type auditChange struct {
+	mu     sync.Mutex
+	before any
+	after  any
+	set    bool
+}
+
+type auditChangeContextKey struct{}
+
+// RecordAuditChange 记录本次写操作的变更前后快照，审计日志会输出 before/after 字段。
+//
+// 只在审计拦截器已为当前请求安装容器时生效，否则返回 false。
+func RecordAuditChange(ctx context.Context, before any, after any) bool {
+	change, ok := ctx.Value(auditChangeContextKey{}).(*auditChange)
+	if !ok {
+		return false
+	}
+	change.mu.Lock()
+	defer change.mu.Unlock()
+	change.before, change.after, change.set = before, after, true
+	return true
+}
+
+// auditMatcher 判断方法是否需要审计。
+type auditMatcher struct {
+	prefixes []string
+	methods  map[string]struct{}
+	skip     map[string]struct{}
+}
+
+func (m *auditMatcher) matches(fullMethod string) bool {
+	if _, ok := m.skip[fullMethod]; ok {
+		return false
+	}
+	if _, ok := m.methods[fullMethod]; ok {
+		return true
+	}
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAuditInterceptor 创建操作审计 unary 拦截器。
+//
+// 按命名约定或显式标注识别写操作，请求结束后输出一条独立于访问日志的审计记录，
+// 包含操作主体（user/app/tenant）、请求报文、handler 通过 RecordAuditChange 提供的变更前后快照与结果状态。
+// 需放在 NewServiceContextUnaryInterceptor 之后。
+func NewAuditInterceptor(log *logger.OperationLogger, options AuditOptions) grpc.UnaryServerInterceptor {
+	options = options.normalize()
+	matcher := &auditMatcher{
+		prefixes: options.WritePrefixes,
+		methods:  buildMethodSet(options.Methods),
+		skip:     buildMethodSet(options.SkipMethods),
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := unaryFullMethod(info)
+		if log == nil || !matcher.matches(method) {
+			return handler(ctx, req)
+		}
+
+		change := &auditChange{}
+		ctx = context.WithValue(ctx, auditChangeContextKey{}, change)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(start)
+
+		code := codes.OK
+		if err != nil {
+			code = status.Code(err)
+		}
+		fields := make([]zap.Field, 0, 16)
+		fields = append(fields,
+			zap.String("protocol", "grpc"),
+			zap.String("path", method),
+			zap.Uint64("duration", uint64(elapsed.Microseconds())),
+			zap.Uint32("status", uint32(code)),
+		)
+		if serviceContext, ok := service.FromContext(ctx); ok {
+			fields = appendNonEmpty(fields,
+				"user_id", serviceContext.UserId,
+				"app_id", serviceContext.AppId,
+				"tenant_id", serviceContext.TenantId,
+				"subject_type", serviceContext.SubjectType,
+				"invoke_app_id", serviceContext.InvokeAppId,
+				"decision_id", serviceContext.DecisionId,
+			)
+		}
+		if request, e := json.Marshal(req); e == nil {
+			fields = appendPayloadField(fields, "request", request, options.MaxPayloadBytes)
+		}
+		change.mu.Lock()
+		if change.set {
+			if before, e := json.Marshal(change.before); e == nil {
+				fields = appendPayloadField(fields, "before", before, options.MaxPayloadBytes)
+			}
+			if after, e := json.Marshal(change.after); e == nil {
+				fields = appendPayloadField(fields, "after", after, options.MaxPayloadBytes)
+			}
+		}
+		change.mu.Unlock()
+
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+			log.WithContextError(ctx, constant.GrpcOperationLog, fields...)
+		} else {
+			log.WithContextInfo(ctx, constant.GrpcOperationLog, fields...)
+		}
+		return resp, err
+	}
+}
+
+// appendNonEmpty 按 key/value 对追加非空字符串字段。
+func appendNonEmpty(fields []zap.Field, pairs ...string) []zap.Field {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			fields = append(fields, zap.String(pairs[i], pairs[i+1]))
+		}
+	}
+	return fields
+}
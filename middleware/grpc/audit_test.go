@@ -0,0 +1,65 @@
+package gm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewAuditInterceptorRecordsWriteMethods(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewAuditInterceptor(logger.NewOperationLogger(zap.New(baseCore)), AuditOptions{
+		Methods:     []string{"/acme.order.v1.OrderService/Approve"},
+		SkipMethods: []string{"/acme.order.v1.OrderService/SetDraft"},
+	})
+	ctx := service.WithContext(context.Background(), &service.Context{UserId: "user-1", TenantId: "tenant-1"})
+	call := func(method string, handler grpc.UnaryHandler) {
+		_, _ = interceptor(ctx, map[string]string{"status": "paid"}, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+	ok := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	call("/acme.order.v1.OrderService/GetOrder", ok)
+	call("/acme.order.v1.OrderService/SetDraft", ok)
+	if got := observed.Len(); got != 0 {
+		t.Fatalf("expected read and skipped methods not to be audited, got %d", got)
+	}
+
+	call("/acme.order.v1.OrderService/UpdateOrder", func(ctx context.Context, req any) (any, error) {
+		if !RecordAuditChange(ctx, map[string]string{"status": "new"}, map[string]string{"status": "paid"}) {
+			t.Fatal("expected audit change container")
+		}
+		return "ok", nil
+	})
+	call("/acme.order.v1.OrderService/Approve", func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.FailedPrecondition, "not paid")
+	})
+
+	entries := observed.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected two audit records, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["log_type"] != "operation" || fields["user_id"] != "user-1" || fields["tenant_id"] != "tenant-1" {
+		t.Fatalf("unexpected audit subject fields: %v", fields)
+	}
+	if fields["before"] != `{"status":"new"}` || fields["after"] != `{"status":"paid"}` {
+		t.Fatalf("unexpected audit change fields: %v", fields)
+	}
+	if entries[1].Level != zapcore.ErrorLevel || entries[1].ContextMap()["status"] != uint32(codes.FailedPrecondition) {
+		t.Fatalf("expected failed annotated method to be audited as error: %v", entries[1].ContextMap())
+	}
+}
+
+func TestRecordAuditChangeWithoutInterceptor(t *testing.T) {
+	if RecordAuditChange(context.Background(), nil, nil) {
+		t.Fatal("expected false without audit interceptor")
+	}
+}
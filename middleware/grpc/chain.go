@@ -0,0 +1,272 @@
+package gm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fireflycore/go-micro/logger"
+	"google.golang.org/grpc"
+)
+
+// 标准拦截器链的阶段名称。
+const (
+	StageRecovery       = "recovery"
+	StageServiceContext = "service_context"
+	StageValidation     = "validation"
+	StageAccessLogger   = "access_logger"
+)
+
+// ChainStage 表示拦截器链中的一个具名阶段，unary 与 stream 拦截器可以只提供其一。
+type ChainStage struct {
+	// Name 表示阶段名称，在同一条链中唯一，用于插入、替换和执行追踪。
+	Name string
+	// Unary 表示该阶段的 unary 拦截器。
+	Unary grpc.UnaryServerInterceptor
+	// Stream 表示该阶段的 stream 拦截器。
+	Stream grpc.StreamServerInterceptor
+	// SkipMethods 表示跳过该阶段的完整 gRPC 方法名，命中时直接调用下一阶段。
+	SkipMethods []string
+}
+
+// Chain 按阶段组装服务端拦截器链，支持插入、替换、删除与按方法跳过。
+//
+// Chain 不是并发安全的，应在服务启动阶段构建完成后再生成 ServerOption。
+type Chain struct {
+	stages []ChainStage
+	trace  InterceptorTraceOptions
+}
+
+// StandardChainOptions 定义标准拦截器链的组成。
+type StandardChainOptions struct {
+	// Recovery 表示 panic 恢复配置。
+	Recovery RecoveryOptions
+	// ServiceContext 表示入口 service.Context 注入与验签配置。
+	ServiceContext ServiceContextInterceptorOptions
+	// AccessLogger 为空时不包含访问日志阶段。
+	AccessLogger *logger.AccessLogger
+	// AccessLoggerOptions 表示访问日志配置。
+	AccessLoggerOptions AccessLoggerOptions
+	// Trace 表示拦截器执行追踪配置，只作用于 unary 链。
+	Trace InterceptorTraceOptions
+}
+
+// NewChain 按给定顺序创建拦截器链。
+func NewChain(stages ...ChainStage) (*Chain, error) {
+	chain := &Chain{}
+	for _, stage := range stages {
+		if err := chain.Append(stage); err != nil {
+			return nil, err
+		}
+	}
+	return chain, nil
+}
+
+// NewStandardChain 创建标准拦截器链：recovery → access_logger → service_context → validation。
+//
+// 访问日志位于入口上下文注入与验签之前，被 authz 拒绝的请求与 panic 恢复后的错误同样会留下访问日志；
+// 内层构建的 service.Context 会回传给访问日志，日志字段不受顺序影响。
+// 业务拦截器（鉴权、配额等）可以通过 InsertAfter 插入到对应阶段之后。
+func NewStandardChain(options StandardChainOptions) *Chain {
+	chain := &Chain{trace: options.Trace}
+	chain.stages = append(chain.stages, ChainStage{
+		Name:   StageRecovery,
+		Unary:  NewRecoveryUnaryInterceptor(options.Recovery),
+		Stream: NewRecoveryStreamInterceptor(options.Recovery),
+	})
+	if options.AccessLogger != nil {
+		chain.stages = append(chain.stages, ChainStage{
+			Name:   StageAccessLogger,
+			Unary:  NewAccessLogger(options.AccessLogger, options.AccessLoggerOptions),
+			Stream: NewStreamAccessLogger(options.AccessLogger, options.AccessLoggerOptions),
+		})
+	}
+	chain.stages = append(chain.stages,
+		ChainStage{
+			Name:   StageServiceContext,
+			Unary:  NewServiceContextUnaryInterceptor(options.ServiceContext),
+			Stream: NewServiceContextStreamInterceptor(options.ServiceContext),
+		},
+		ChainStage{
+			Name:  StageValidation,
+			Unary: ValidationErrorToInvalidArgument(),
+		},
+	)
+	return chain
+}
+
+// WithTrace 设置 unary 链的拦截器执行追踪。
+func (c *Chain) WithTrace(options InterceptorTraceOptions) *Chain {
+	c.trace = options
+	return c
+}
+
+// Names 按执行顺序返回阶段名称。
+func (c *Chain) Names() []string {
+	names := make([]string, 0, len(c.stages))
+	for _, stage := range c.stages {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+// Append 在链尾追加阶段。
+func (c *Chain) Append(stage ChainStage) error {
+	return c.insert(len(c.stages), stage)
+}
+
+// InsertBefore 在指定阶段之前插入新阶段。
+func (c *Chain) InsertBefore(name string, stage ChainStage) error {
+	index, err := c.indexOf(name)
+	if err != nil {
+		return err
+	}
+	return c.insert(index, stage)
+}
+
+// InsertAfter 在指定阶段之后插入新阶段。
+func (c *Chain) InsertAfter(name string, stage ChainStage) error {
+	index, err := c.indexOf(name)
+	if err != nil {
+		return err
+	}
+	return c.insert(index+1, stage)
+}
+
+// Replace 用新阶段替换同名阶段，位置保持不变；stage.Name 为空时沿用原名称。
+func (c *Chain) Replace(name string, stage ChainStage) error {
+	index, err := c.indexOf(name)
+	if err != nil {
+		return err
+	}
+	if stage.Name == "" {
+		stage.Name = name
+	}
+	if stage.Name != name {
+		if _, err := c.indexOf(stage.Name); err == nil {
+			return fmt.Errorf("%w: %s", ErrChainStageDuplicate, stage.Name)
+		}
+	}
+	c.stages[index] = stage
+	return nil
+}
+
+// Remove 删除指定阶段。
+func (c *Chain) Remove(name string) error {
+	index, err := c.indexOf(name)
+	if err != nil {
+		return err
+	}
+	c.stages = append(c.stages[:index], c.stages[index+1:]...)
+	return nil
+}
+
+// Skip 为指定阶段追加跳过的完整 gRPC 方法名。
+func (c *Chain) Skip(name string, methods ...string) error {
+	index, err := c.indexOf(name)
+	if err != nil {
+		return err
+	}
+	c.stages[index].SkipMethods = append(c.stages[index].SkipMethods, methods...)
+	return nil
+}
+
+// UnaryInterceptors 生成可传给 grpc.ChainUnaryInterceptor 的拦截器切片。
+func (c *Chain) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+	named := make([]NamedUnaryInterceptor, 0, len(c.stages))
+	for _, stage := range c.stages {
+		if stage.Unary == nil {
+			continue
+		}
+		named = append(named, NamedUnaryInterceptor{
+			Name:        stage.Name,
+			Interceptor: skipUnaryInterceptor(stage.Unary, stage.SkipMethods),
+		})
+	}
+	return NewInterceptorTrace(c.trace, named...)
+}
+
+// StreamInterceptors 生成可传给 grpc.ChainStreamInterceptor 的拦截器切片。
+func (c *Chain) StreamInterceptors() []grpc.StreamServerInterceptor {
+	result := make([]grpc.StreamServerInterceptor, 0, len(c.stages))
+	for _, stage := range c.stages {
+		if stage.Stream == nil {
+			continue
+		}
+		result = append(result, skipStreamInterceptor(stage.Stream, stage.SkipMethods))
+	}
+	return result
+}
+
+// ServerOptions 生成同时包含 unary 与 stream 链的 grpc.ServerOption。
+func (c *Chain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.UnaryInterceptors()...),
+		grpc.ChainStreamInterceptor(c.StreamInterceptors()...),
+	}
+}
+
+// insert 在 index 处插入阶段，并校验名称。
+func (c *Chain) insert(index int, stage ChainStage) error {
+	if stage.Name == "" {
+		return ErrChainStageNameEmpty
+	}
+	if _, err := c.indexOf(stage.Name); err == nil {
+		return fmt.Errorf("%w: %s", ErrChainStageDuplicate, stage.Name)
+	}
+	c.stages = append(c.stages, ChainStage{})
+	copy(c.stages[index+1:], c.stages[index:])
+	c.stages[index] = stage
+	return nil
+}
+
+// indexOf 返回阶段下标。
+func (c *Chain) indexOf(name string) (int, error) {
+	for index, stage := range c.stages {
+		if stage.Name == name {
+			return index, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %s", ErrChainStageNotFound, name)
+}
+
+// buildMethodSet 把方法列表整理为集合；为空时返回 nil。
+func buildMethodSet(methods []string) map[string]struct{} {
+	if len(methods) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		if method != "" {
+			set[method] = struct{}{}
+		}
+	}
+	return set
+}
+
+// skipUnaryInterceptor 包装拦截器，命中跳过方法时直接调用下一阶段。
+func skipUnaryInterceptor(interceptor grpc.UnaryServerInterceptor, methods []string) grpc.UnaryServerInterceptor {
+	skip := buildMethodSet(methods)
+	if len(skip) == 0 {
+		return interceptor
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := skip[unaryFullMethod(info)]; ok {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// skipStreamInterceptor 是 skipUnaryInterceptor 的 stream 版本。
+func skipStreamInterceptor(interceptor grpc.StreamServerInterceptor, methods []string) grpc.StreamServerInterceptor {
+	skip := buildMethodSet(methods)
+	if len(skip) == 0 {
+		return interceptor
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := skip[streamFullMethod(info)]; ok {
+			return handler(srv, ss)
+		}
+		return interceptor(srv, ss, info, handler)
+	}
+}
@@ -0,0 +1,153 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChainStageOperations(t *testing.T) {
+	var order []string
+	chain, err := NewChain(recordingStage("a", &order), recordingStage("c", &order))
+	if err != nil {
+		t.Fatalf("new chain failed: %v", err)
+	}
+	if err := chain.InsertBefore("c", recordingStage("b", &order)); err != nil {
+		t.Fatalf("insert before failed: %v", err)
+	}
+	if err := chain.InsertAfter("c", recordingStage("d", &order)); err != nil {
+		t.Fatalf("insert after failed: %v", err)
+	}
+	if err := chain.Replace("a", recordingStage("", &order)); err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	if err := chain.Remove("d"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := chain.Skip("b", "/example.Service/Health"); err != nil {
+		t.Fatalf("skip failed: %v", err)
+	}
+	if got := chain.Names(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected stage order: %v", got)
+	}
+
+	if err := chain.Append(recordingStage("a", &order)); !errors.Is(err, ErrChainStageDuplicate) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	if err := chain.InsertAfter("missing", recordingStage("x", &order)); !errors.Is(err, ErrChainStageNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if err := chain.Append(ChainStage{}); !errors.Is(err, ErrChainStageNameEmpty) {
+		t.Fatalf("expected empty name error, got %v", err)
+	}
+
+	runChain(chain, "/example.Service/Get")
+	if !reflect.DeepEqual(order, []string{"replaced", "b", "c"}) {
+		t.Fatalf("unexpected execution order: %v", order)
+	}
+	order = nil
+	runChain(chain, "/example.Service/Health")
+	if !reflect.DeepEqual(order, []string{"replaced", "c"}) {
+		t.Fatalf("expected skipped stage to be bypassed, got %v", order)
+	}
+}
+
+func TestNewStandardChainStages(t *testing.T) {
+	chain := NewStandardChain(StandardChainOptions{})
+	if got := chain.Names(); !reflect.DeepEqual(got, []string{StageRecovery, StageServiceContext, StageValidation}) {
+		t.Fatalf("unexpected standard stages: %v", got)
+	}
+	if got := len(chain.StreamInterceptors()); got != 2 {
+		t.Fatalf("expected validation to have no stream stage, got %d stream interceptors", got)
+	}
+}
+
+func TestStandardChainLogsAuthzRejection(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	chain := NewStandardChain(StandardChainOptions{
+		ServiceContext: ServiceContextInterceptorOptions{
+			ServiceAppId:      "order-app",
+			AuthzVerification: &service.AuthzSignVerificationOptions{},
+			AuthzSkipMethods:  []string{"/example.Service/Public"},
+		},
+		AccessLogger: logger.NewAccessLogger(zap.New(baseCore)),
+	})
+	if got := chain.Names(); !reflect.DeepEqual(got, []string{StageRecovery, StageAccessLogger, StageServiceContext, StageValidation}) {
+		t.Fatalf("unexpected standard stages: %v", got)
+	}
+
+	// 缺少 authz JWS 的请求在 service_context 被拒绝，仍然留下访问日志。
+	_, err := runUnaryChain(context.Background(), chain.UnaryInterceptors(), &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			t.Fatal("handler should not run for rejected request")
+			return nil, nil
+		})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	entries := observed.All()
+	if len(entries) != 1 || entries[0].ContextMap()["status"] != uint32(codes.Unauthenticated) {
+		t.Fatalf("expected one access log entry for rejected request, got %+v", entries)
+	}
+
+	// 放行的请求仍能在访问日志中读取内层构建的 service.Context 字段。
+	if _, err := runUnaryChain(context.Background(), chain.UnaryInterceptors(), &grpc.UnaryServerInfo{FullMethod: "/example.Service/Public"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries = observed.All()
+	if len(entries) != 2 || entries[1].ContextMap()["service_app_id"] != "order-app" {
+		t.Fatalf("expected service context fields in access log, got %+v", entries[len(entries)-1].ContextMap())
+	}
+}
+
+func TestStandardChainLogsRecoveredPanic(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	chain := NewStandardChain(StandardChainOptions{AccessLogger: logger.NewAccessLogger(zap.New(baseCore))})
+
+	_, err := runUnaryChain(context.Background(), chain.UnaryInterceptors(), &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) { panic("boom") })
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	var accessLogs int
+	for _, entry := range observed.All() {
+		if entry.ContextMap()["log_type"] == "access" {
+			accessLogs++
+		}
+	}
+	if accessLogs != 1 {
+		t.Fatalf("expected panic to be access-logged once, got %d", accessLogs)
+	}
+}
+
+// recordingStage 创建按名称记录执行顺序的阶段；name 为空时记录为 replaced。
+func recordingStage(name string, order *[]string) ChainStage {
+	label := name
+	if label == "" {
+		label = "replaced"
+	}
+	return ChainStage{
+		Name: name,
+		Unary: func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			*order = append(*order, label)
+			return handler(ctx, req)
+		},
+	}
+}
+
+// runChain 以空 handler 执行链上的 unary 拦截器。
+func runChain(chain *Chain, method string) {
+	_, _ = runUnaryChain(context.Background(), chain.UnaryInterceptors(), &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req any) (any, error) { return nil, nil })
+}
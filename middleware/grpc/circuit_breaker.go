@@ -0,0 +1,271 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultCircuitWindow 是统计错误率的默认窗口。
+	DefaultCircuitWindow = 10 * time.Second
+	// DefaultCircuitMinRequests 是窗口内触发熔断判断的最小请求数。
+	DefaultCircuitMinRequests = 20
+	// DefaultCircuitFailureRatio 是触发熔断的默认错误率。
+	DefaultCircuitFailureRatio = 0.5
+	// DefaultCircuitOpenTimeout 是熔断打开后进入半开探测前的默认等待时长。
+	DefaultCircuitOpenTimeout = 30 * time.Second
+	// DefaultCircuitHalfOpenRequests 是半开状态允许的默认探测请求数。
+	DefaultCircuitHalfOpenRequests = 1
+)
+
+// DefaultCircuitFailureCodes 是默认计为下游故障的 gRPC code；业务错误（如 NotFound）不计入。
+var DefaultCircuitFailureCodes = []codes.Code{
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.Internal,
+	codes.Unknown,
+	codes.ResourceExhausted,
+}
+
+// CircuitState 表示熔断器状态。
+type CircuitState int
+
+const (
+	// CircuitClosed 表示正常放行。
+	CircuitClosed CircuitState = iota
+	// CircuitOpen 表示快速失败，不再请求下游。
+	CircuitOpen
+	// CircuitHalfOpen 表示放行少量探测请求，根据结果决定关闭或重新打开。
+	CircuitHalfOpen
+)
+
+// String 返回状态名称，便于日志与指标使用。
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions 定义客户端熔断拦截器配置。
+type CircuitBreakerOptions struct {
+	// Window 表示统计错误率的固定窗口；未设置时使用 DefaultCircuitWindow。
+	Window time.Duration
+	// MinRequests 表示窗口内请求数达到该值后才判断错误率；未设置时使用 DefaultCircuitMinRequests。
+	MinRequests int
+	// FailureRatio 表示触发熔断的错误率，取值 (0, 1]；未设置时使用 DefaultCircuitFailureRatio。
+	FailureRatio float64
+	// OpenTimeout 表示熔断打开后进入半开探测前的等待时长；未设置时使用 DefaultCircuitOpenTimeout。
+	OpenTimeout time.Duration
+	// HalfOpenRequests 表示半开状态允许的探测请求数；未设置时使用 DefaultCircuitHalfOpenRequests。
+	HalfOpenRequests int
+	// FailureCodes 表示计为下游故障的 gRPC code；未设置时使用 DefaultCircuitFailureCodes。
+	FailureCodes []codes.Code
+	// OnStateChange 在熔断器状态变化时回调，key 为 "服务名 full method"；为空时不回调。
+	OnStateChange func(key string, from CircuitState, to CircuitState)
+}
+
+// normalize 补齐默认值。
+func (o CircuitBreakerOptions) normalize() CircuitBreakerOptions {
+	if o.Window <= 0 {
+		o.Window = DefaultCircuitWindow
+	}
+	if o.MinRequests <= 0 {
+		o.MinRequests = DefaultCircuitMinRequests
+	}
+	if o.FailureRatio <= 0 || o.FailureRatio > 1 {
+		o.FailureRatio = DefaultCircuitFailureRatio
+	}
+	if o.OpenTimeout <= 0 {
+		o.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+	if o.HalfOpenRequests <= 0 {
+		o.HalfOpenRequests = DefaultCircuitHalfOpenRequests
+	}
+	if len(o.FailureCodes) == 0 {
+		o.FailureCodes = DefaultCircuitFailureCodes
+	}
+	return o
+}
+
+// circuitBreaker 是单个目标（服务 + 方法）的熔断器。
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state CircuitState
+	// windowStart 表示当前统计窗口起点。
+	windowStart time.Time
+	// requests / failures 表示当前窗口内的请求数与故障数。
+	requests int
+	failures int
+	// openedAt 表示最近一次打开的时间。
+	openedAt time.Time
+	// probes 表示半开状态下已放行的探测请求数，successes 表示其中已成功的数量。
+	probes    int
+	successes int
+	// generation 在每次状态切换时递增，用于识别跨状态完成的过期请求。
+	generation uint64
+}
+
+// circuitAdmission 记录请求放行时所处的熔断器代际，done 时据此丢弃过期结果。
+type circuitAdmission struct {
+	breaker    *circuitBreaker
+	generation uint64
+}
+
+// circuitBreakerGroup 按目标维护熔断器。
+type circuitBreakerGroup struct {
+	options  CircuitBreakerOptions
+	failures map[codes.Code]struct{}
+	breakers sync.Map
+	now      func() time.Time
+}
+
+// allow 判断请求是否放行；返回的 admission 记录放行时的代际，供 done 判断结果是否仍然有效。
+func (g *circuitBreakerGroup) allow(key string) (circuitAdmission, bool) {
+	value, _ := g.breakers.LoadOrStore(key, &circuitBreaker{windowStart: g.now()})
+	breaker := value.(*circuitBreaker)
+	now := g.now()
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	switch breaker.state {
+	case CircuitOpen:
+		if now.Sub(breaker.openedAt) < g.options.OpenTimeout {
+			return circuitAdmission{breaker: breaker, generation: breaker.generation}, false
+		}
+		g.transition(key, breaker, CircuitHalfOpen, now)
+		fallthrough
+	case CircuitHalfOpen:
+		admission := circuitAdmission{breaker: breaker, generation: breaker.generation}
+		if breaker.probes >= g.options.HalfOpenRequests {
+			return admission, false
+		}
+		breaker.probes++
+		return admission, true
+	default:
+		if now.Sub(breaker.windowStart) >= g.options.Window {
+			breaker.windowStart, breaker.requests, breaker.failures = now, 0, 0
+		}
+		return circuitAdmission{breaker: breaker, generation: breaker.generation}, true
+	}
+}
+
+// done 记录请求结果并驱动状态变化。
+//
+// 放行后熔断器已切换状态的请求（例如 closed 时放行、半开期间才返回的慢请求）属于过期结果，直接忽略，
+// 避免其被误当作探测结果提前关闭或重新打开熔断器。
+//
+// 调用方主动取消的请求既不代表下游健康也不代表故障：不计入窗口统计，半开状态下只归还探测名额。
+func (g *circuitBreakerGroup) done(key string, admission circuitAdmission, err error) {
+	_, failed := g.failures[status.Code(err)]
+	cancelled := circuitCancelled(err)
+	now := g.now()
+	breaker := admission.breaker
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if admission.generation != breaker.generation {
+		return
+	}
+	if cancelled {
+		if breaker.state == CircuitHalfOpen && breaker.probes > 0 {
+			breaker.probes--
+		}
+		return
+	}
+	switch breaker.state {
+	case CircuitHalfOpen:
+		// 任一探测失败立即重新打开；HalfOpenRequests 个探测全部成功后才关闭。
+		if failed {
+			g.transition(key, breaker, CircuitOpen, now)
+			return
+		}
+		breaker.successes++
+		if breaker.successes >= g.options.HalfOpenRequests {
+			g.transition(key, breaker, CircuitClosed, now)
+		}
+	case CircuitClosed:
+		breaker.requests++
+		if failed {
+			breaker.failures++
+		}
+		if breaker.requests >= g.options.MinRequests &&
+			float64(breaker.failures)/float64(breaker.requests) >= g.options.FailureRatio {
+			g.transition(key, breaker, CircuitOpen, now)
+		}
+	}
+}
+
+// circuitCancelled 判断请求是否因调用方取消而结束。
+func circuitCancelled(err error) bool {
+	return status.Code(err) == codes.Canceled || errors.Is(err, context.Canceled)
+}
+
+// transition 切换状态并重置对应计数；调用方需持有 breaker.mu。
+func (g *circuitBreakerGroup) transition(key string, breaker *circuitBreaker, to CircuitState, now time.Time) {
+	from := breaker.state
+	if from == to {
+		return
+	}
+	breaker.state = to
+	breaker.generation++
+	breaker.probes, breaker.successes = 0, 0
+	breaker.requests, breaker.failures, breaker.windowStart = 0, 0, now
+	if to == CircuitOpen {
+		breaker.openedAt = now
+	}
+	if g.options.OnStateChange != nil {
+		g.options.OnStateChange(key, from, to)
+	}
+}
+
+// NewCircuitBreakerInterceptor 创建按目标（服务名 + full method）隔离的客户端熔断 unary 拦截器。
+//
+// 窗口内请求数达到 MinRequests 且故障率达到 FailureRatio 时打开熔断，期间直接返回 Unavailable，
+// 并通过 rpcerror 标记为不可重试，避免外层重试拦截器继续冲击下游；
+// OpenTimeout 后进入半开状态放行少量探测请求，探测成功则关闭，失败则重新打开。
+func NewCircuitBreakerInterceptor(options CircuitBreakerOptions) grpc.UnaryClientInterceptor {
+	group := newCircuitBreakerGroup(options)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		key := targetServiceName(cc) + " " + method
+		admission, allowed := group.allow(key)
+		if !allowed {
+			return rpcerror.WithRetryHint(
+				status.Error(codes.Unavailable, "circuit breaker is open"),
+				rpcerror.RetryHint{Retryable: false},
+			)
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		outcome := err
+		if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+			// 调用方已放弃时下游返回的任何错误都不反映下游健康状况。
+			outcome = context.Canceled
+		}
+		group.done(key, admission, outcome)
+		return err
+	}
+}
+
+// newCircuitBreakerGroup 创建熔断器组。
+func newCircuitBreakerGroup(options CircuitBreakerOptions) *circuitBreakerGroup {
+	options = options.normalize()
+	failures := make(map[codes.Code]struct{}, len(options.FailureCodes))
+	for _, code := range options.FailureCodes {
+		failures[code] = struct{}{}
+	}
+	return &circuitBreakerGroup{options: options, failures: failures, now: time.Now}
+}
@@ -0,0 +1,222 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreakerOpensHalfOpensAndCloses(t *testing.T) {
+	var transitions []string
+	group := newCircuitBreakerGroup(CircuitBreakerOptions{
+		MinRequests:  4,
+		FailureRatio: 0.5,
+		OpenTimeout:  time.Minute,
+		OnStateChange: func(key string, from CircuitState, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	now := time.Unix(1710000000, 0)
+	group.now = func() time.Time { return now }
+
+	const key = "order /acme.order.v1.OrderService/Get"
+	call := func(err error) bool {
+		admission, allowed := group.allow(key)
+		if allowed {
+			group.done(key, admission, err)
+		}
+		return allowed
+	}
+
+	// 业务错误不计入故障。
+	call(status.Error(codes.NotFound, "missing"))
+	call(nil)
+	call(status.Error(codes.Unavailable, "down"))
+	call(status.Error(codes.Unavailable, "down"))
+	if call(nil) {
+		t.Fatalf("expected circuit to be open after failure ratio reached")
+	}
+
+	// 打开期满后进入半开，只放行一个探测请求。
+	now = now.Add(2 * time.Minute)
+	admission, allowed := group.allow(key)
+	if !allowed {
+		t.Fatalf("expected probe to be allowed in half-open state")
+	}
+	if _, allowed := group.allow(key); allowed {
+		t.Fatalf("expected extra probe to be rejected")
+	}
+	group.done(key, admission, nil)
+	if !call(nil) {
+		t.Fatalf("expected circuit to be closed after successful probe")
+	}
+
+	want := []string{"closed->open", "open->half_open", "half_open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("unexpected transitions: %v", transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("unexpected transitions: %v", transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerInterceptorFailsFastWithoutRetryHint(t *testing.T) {
+	interceptor := NewCircuitBreakerInterceptor(CircuitBreakerOptions{MinRequests: 1, FailureRatio: 1})
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	_ = interceptor(context.Background(), "/example.Service/Get", nil, nil, nil, invoker)
+	err := interceptor(context.Background(), "/example.Service/Get", nil, nil, nil, invoker)
+	if status.Convert(err).Message() != "circuit breaker is open" {
+		t.Fatalf("expected open circuit error, got %v", err)
+	}
+	if retryable, _ := rpcerror.IsRetryable(err); retryable {
+		t.Fatalf("expected open circuit error to be marked non-retryable")
+	}
+	// 其它方法使用独立熔断器。
+	if err := interceptor(context.Background(), "/example.Service/List", nil, nil, nil, invoker); status.Convert(err).Message() != "down" {
+		t.Fatalf("expected other method to reach invoker, got %v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRequiresAllProbesToSucceed(t *testing.T) {
+	group := newCircuitBreakerGroup(CircuitBreakerOptions{
+		MinRequests:      1,
+		FailureRatio:     1,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 3,
+	})
+	now := time.Unix(1710000000, 0)
+	group.now = func() time.Time { return now }
+
+	const key = "order /acme.order.v1.OrderService/Get"
+	admission, _ := group.allow(key)
+	group.done(key, admission, status.Error(codes.Unavailable, "down"))
+
+	now = now.Add(2 * time.Minute)
+	probes := make([]circuitAdmission, 0, 3)
+	for range 3 {
+		probe, allowed := group.allow(key)
+		if !allowed {
+			t.Fatalf("expected probe to be allowed in half-open state")
+		}
+		probes = append(probes, probe)
+	}
+
+	// 第一个探测成功不足以关闭熔断器。
+	group.done(key, probes[0], nil)
+	if _, allowed := group.allow(key); allowed {
+		t.Fatalf("expected circuit to stay half-open after a single successful probe")
+	}
+	group.done(key, probes[1], nil)
+	group.done(key, probes[2], nil)
+	if _, allowed := group.allow(key); !allowed {
+		t.Fatalf("expected circuit to be closed after all probes succeeded")
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleCompletions(t *testing.T) {
+	var transitions []string
+	group := newCircuitBreakerGroup(CircuitBreakerOptions{
+		MinRequests:  1,
+		FailureRatio: 1,
+		OpenTimeout:  time.Minute,
+		OnStateChange: func(key string, from CircuitState, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	now := time.Unix(1710000000, 0)
+	group.now = func() time.Time { return now }
+
+	const key = "order /acme.order.v1.OrderService/Get"
+	// closed 时放行两个请求，其中一个在半开期间才返回。
+	slowSuccess, _ := group.allow(key)
+	slowFailure, _ := group.allow(key)
+	failing, _ := group.allow(key)
+	group.done(key, failing, status.Error(codes.Unavailable, "down"))
+
+	now = now.Add(2 * time.Minute)
+	probe, allowed := group.allow(key)
+	if !allowed {
+		t.Fatalf("expected probe to be allowed in half-open state")
+	}
+
+	// 过期的成功不能关闭熔断器，过期的失败也不能重新打开。
+	group.done(key, slowSuccess, nil)
+	group.done(key, slowFailure, status.Error(codes.Unavailable, "down"))
+	if _, allowed := group.allow(key); allowed {
+		t.Fatalf("expected stale completions to leave the circuit half-open")
+	}
+
+	group.done(key, probe, nil)
+	if _, allowed := group.allow(key); !allowed {
+		t.Fatalf("expected circuit to be closed after the probe succeeded")
+	}
+
+	want := []string{"closed->open", "open->half_open", "half_open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("unexpected transitions: %v", transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("unexpected transitions: %v", transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	group := newCircuitBreakerGroup(CircuitBreakerOptions{
+		MinRequests:  2,
+		FailureRatio: 0.5,
+		OpenTimeout:  time.Minute,
+	})
+	now := time.Unix(1710000000, 0)
+	group.now = func() time.Time { return now }
+
+	const key = "order /acme.order.v1.OrderService/Get"
+	call := func(err error) bool {
+		admission, allowed := group.allow(key)
+		if allowed {
+			group.done(key, admission, err)
+		}
+		return allowed
+	}
+
+	// closed 状态下取消不计入请求数，不会稀释故障率。
+	call(status.Error(codes.Unavailable, "down"))
+	for range 5 {
+		call(status.Error(codes.Canceled, "caller gave up"))
+		call(context.Canceled)
+	}
+	call(status.Error(codes.Unavailable, "down"))
+	if call(nil) {
+		t.Fatalf("expected cancellations not to dilute the failure ratio")
+	}
+
+	// 半开探测被取消时不关闭熔断器，只归还探测名额。
+	now = now.Add(2 * time.Minute)
+	probe, allowed := group.allow(key)
+	if !allowed {
+		t.Fatalf("expected probe to be allowed in half-open state")
+	}
+	group.done(key, probe, status.Error(codes.Canceled, "caller gave up"))
+	probe, allowed = group.allow(key)
+	if !allowed {
+		t.Fatalf("expected cancelled probe to release its slot")
+	}
+	if _, allowed := group.allow(key); allowed {
+		t.Fatalf("expected cancelled probe not to close the circuit")
+	}
+	group.done(key, probe, nil)
+	if !call(nil) {
+		t.Fatalf("expected circuit to be closed after a completed successful probe")
+	}
+}
@@ -0,0 +1,96 @@
+package gm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewClientAccessLogger 创建出站调用访问日志 unary 拦截器，是 NewAccessLogger 的客户端镜像。
+//
+// 每次出站调用记录目标服务、实际连接的对端地址、耗时与状态；trace 字段由 ctx 自动关联，
+// 与下游服务端访问日志按 trace_id 对齐即可还原调用图。跳过、截断、采样与慢请求配置与服务端一致。
+func NewClientAccessLogger(log *logger.AccessLogger, options ...AccessLoggerOptions) grpc.UnaryClientInterceptor {
+	config := buildAccessLoggerConfig(options...)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if log == nil || shouldSkipAccessLog(method, config.skipMethods) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		// 通过 grpc.Peer 取回本次调用实际连接的后端地址。
+		var callPeer peer.Peer
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&callPeer))...)
+		elapsed := time.Since(start)
+		if !config.sampled(method, elapsed, err) {
+			return err
+		}
+
+		code := codes.OK
+		if err != nil {
+			code = status.Code(err)
+		}
+
+		fields := make([]zap.Field, 0, 20)
+		fields = append(fields,
+			zap.String("log_type", "access"),
+			zap.String("protocol", "grpc"),
+			zap.String("kind", "client"),
+			zap.String("method", constant.RequestMethodGrpcString),
+			zap.String("path", method),
+			zap.Uint64("duration", uint64(elapsed.Microseconds())),
+			zap.Uint32("status", uint32(code)),
+		)
+		if cc != nil {
+			fields = append(fields,
+				zap.String("target", cc.Target()),
+				zap.String("target_service_app_id", targetServiceName(cc)),
+			)
+		}
+		if callPeer.Addr != nil {
+			fields = append(fields, zap.String("peer_addr", callPeer.Addr.String()))
+		}
+		// 当前进程作为调用方的身份取自入口注入的 service.Context。
+		if serviceContext, ok := service.FromContext(ctx); ok {
+			if serviceContext.ServiceAppId != "" {
+				fields = append(fields, zap.String("service_app_id", serviceContext.ServiceAppId))
+			}
+			if serviceContext.ServiceInstanceId != "" {
+				fields = append(fields, zap.String("service_instance_id", serviceContext.ServiceInstanceId))
+			}
+		}
+		if request, e := json.Marshal(req); e == nil {
+			fields = appendPayloadField(fields, "request", request, config.maxPayloadBytes)
+		}
+		// 失败调用的 reply 没有意义，只在成功时记录。
+		if err == nil {
+			if response, e := json.Marshal(reply); e == nil {
+				fields = appendPayloadField(fields, "response", response, config.maxPayloadBytes)
+			}
+		}
+		fields = config.flagSlow(ctx, method, elapsed, fields)
+
+		if err != nil {
+			fields = append(fields,
+				zap.String("error_class", rpcerror.Classify(code)),
+				zap.String("error_origin", rpcerror.Origin(err)),
+				zap.Error(err),
+			)
+			log.WithContextError(ctx, constant.GrpcClientAccessLog, fields...)
+			return err
+		}
+		log.WithContextInfo(ctx, constant.GrpcClientAccessLog, fields...)
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package gm
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewClientAccessLoggerRecordsOutgoingCall(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewClientAccessLogger(logger.NewAccessLogger(zap.New(baseCore)))
+
+	conn, err := grpc.NewClient("dns:///auth.default.svc.cluster.local:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx := service.WithContext(context.Background(), &service.Context{ServiceAppId: "order"})
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if p, ok := opt.(grpc.PeerCallOption); ok {
+				p.PeerAddr.Addr = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 9090}
+			}
+		}
+		return status.Error(codes.NotFound, "missing")
+	}
+	err = interceptor(ctx, "/auth.v1.AuthService/Check", "k", nil, conn, invoker)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := observed.All()
+	if len(entries) != 1 || entries[0].Level != zapcore.ErrorLevel {
+		t.Fatalf("expected one error access log, got %v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if fields["kind"] != "client" || fields["path"] != "/auth.v1.AuthService/Check" || fields["status"] != uint32(codes.NotFound) {
+		t.Fatalf("unexpected base fields: %v", fields)
+	}
+	if fields["target_service_app_id"] != "auth" || fields["peer_addr"] != "10.0.0.2:9090" || fields["service_app_id"] != "order" {
+		t.Fatalf("unexpected call graph fields: %v", fields)
+	}
+	if _, ok := fields["response"]; ok {
+		t.Fatalf("expected no response payload on failure: %v", fields)
+	}
+}
+
+func TestNewClientAccessLoggerSkipsConfiguredMethod(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewClientAccessLogger(logger.NewAccessLogger(zap.New(baseCore)), AccessLoggerOptions{
+		SkipMethods: []string{"/auth.v1.AuthService/Ping"},
+	})
+
+	called := false
+	_ = interceptor(context.Background(), "/auth.v1.AuthService/Ping", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			called = true
+			return nil
+		})
+	if !called || observed.Len() != 0 {
+		t.Fatalf("expected call to pass through without logging, called=%v logs=%d", called, observed.Len())
+	}
+}
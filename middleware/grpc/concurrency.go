@@ -0,0 +1,133 @@
+package gm
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ConcurrencyLimitOptions 定义在途请求上限配置。
+type ConcurrencyLimitOptions struct {
+	// MaxInFlight 表示全服务在途请求上限；非正数表示不限制。
+	MaxInFlight int
+	// MethodLimits 表示按 full method 的在途请求上限，与全局上限同时生效。
+	MethodLimits map[string]int
+	// QueueTimeout 表示超限请求最多排队等待的时长；0 表示立即拒绝。
+	// 等待同时受请求自身 deadline 约束。
+	QueueTimeout time.Duration
+	// SkipMethods 表示不受限制的 full method；gRPC health check 始终不受限制。
+	SkipMethods []string
+}
+
+// semaphore 是基于带缓冲 channel 的计数信号量。
+type semaphore chan struct{}
+
+// concurrencyLimiter 保存全局与方法级信号量。
+type concurrencyLimiter struct {
+	global       semaphore
+	methods      map[string]semaphore
+	skip         map[string]struct{}
+	queueTimeout time.Duration
+}
+
+// newConcurrencyLimiter 根据配置预先创建信号量。
+func newConcurrencyLimiter(options ConcurrencyLimitOptions) *concurrencyLimiter {
+	limiter := &concurrencyLimiter{
+		methods:      make(map[string]semaphore, len(options.MethodLimits)),
+		skip:         buildMethodSet(options.SkipMethods),
+		queueTimeout: options.QueueTimeout,
+	}
+	if options.MaxInFlight > 0 {
+		limiter.global = make(semaphore, options.MaxInFlight)
+	}
+	for method, limit := range options.MethodLimits {
+		if limit > 0 {
+			limiter.methods[method] = make(semaphore, limit)
+		}
+	}
+	return limiter
+}
+
+// acquire 依次获取方法级与全局名额，返回释放函数；超限时返回 ResourceExhausted。
+func (l *concurrencyLimiter) acquire(ctx context.Context, method string) (func(), error) {
+	if _, ok := l.skip[method]; ok || isHealthMethod(method) {
+		return func() {}, nil
+	}
+
+	var deadline <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var acquired []semaphore
+	release := func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+	for _, sem := range []semaphore{l.methods[method], l.global} {
+		if sem == nil {
+			continue
+		}
+		if err := sem.take(ctx, deadline, l.queueTimeout > 0); err != nil {
+			release()
+			return nil, err
+		}
+		acquired = append(acquired, sem)
+	}
+	return release, nil
+}
+
+// take 获取一个名额；wait 为 false 时不排队。
+func (s semaphore) take(ctx context.Context, deadline <-chan time.Time, wait bool) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	default:
+	}
+	if !wait {
+		return status.Error(codes.ResourceExhausted, "too many concurrent requests")
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-deadline:
+		return status.Error(codes.ResourceExhausted, "too many concurrent requests")
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// NewConcurrencyLimitUnaryInterceptor 创建在途请求上限 unary 拦截器。
+//
+// 上游重试风暴时，超出上限的请求直接返回 ResourceExhausted（或排队至 QueueTimeout），
+// 避免服务被压垮后所有请求一起超时。
+func NewConcurrencyLimitUnaryInterceptor(options ConcurrencyLimitOptions) grpc.UnaryServerInterceptor {
+	limiter := newConcurrencyLimiter(options)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := limiter.acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// NewConcurrencyLimitStreamInterceptor 创建在途请求上限 stream 拦截器，名额在整个流结束后释放。
+func NewConcurrencyLimitStreamInterceptor(options ConcurrencyLimitOptions) grpc.StreamServerInterceptor {
+	limiter := newConcurrencyLimiter(options)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := limiter.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
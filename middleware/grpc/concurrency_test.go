@@ -0,0 +1,83 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitUnaryInterceptorRejectsBeyondMethodLimit(t *testing.T) {
+	interceptor := NewConcurrencyLimitUnaryInterceptor(ConcurrencyLimitOptions{
+		MaxInFlight:  10,
+		MethodLimits: map[string]int{"/example.Service/Export": 1},
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Export"},
+			func(ctx context.Context, req any) (any, error) {
+				close(started)
+				<-release
+				return nil, nil
+			})
+		done <- err
+	}()
+	<-started
+
+	// 同方法第二个请求超出方法级上限，立即拒绝。
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Export"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil })
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	// 其它方法只受全局上限约束。
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("unexpected error for other method: %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 名额释放后可以再次进入。
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Export"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("expected slot to be released, got %v", err)
+	}
+}
+
+func TestConcurrencyLimitUnaryInterceptorQueuesUntilTimeout(t *testing.T) {
+	interceptor := NewConcurrencyLimitUnaryInterceptor(ConcurrencyLimitOptions{
+		MaxInFlight:  1,
+		QueueTimeout: 200 * time.Millisecond,
+	})
+
+	started := make(chan struct{})
+	go func() {
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+			func(ctx context.Context, req any) (any, error) {
+				close(started)
+				time.Sleep(30 * time.Millisecond)
+				return nil, nil
+			})
+	}()
+	<-started
+
+	// 排队等待前一个请求结束后获得名额。
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("expected queued request to succeed, got %v", err)
+	}
+	// health check 不受限制。
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: grpcHealthCheckFullMethod},
+		func(ctx context.Context, req any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("expected health check to bypass limit, got %v", err)
+	}
+}
@@ -10,6 +10,19 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+var (
+	// ErrChainStageNotFound 表示拦截器链中不存在指定名称的阶段。
+	ErrChainStageNotFound = errors.New("chain stage not found")
+	// ErrChainStageDuplicate 表示拦截器链中已存在同名阶段。
+	ErrChainStageDuplicate = errors.New("chain stage already exists")
+	// ErrChainStageNameEmpty 表示阶段名称为空。
+	ErrChainStageNameEmpty = errors.New("chain stage name is empty")
+	// ErrIPFilterInvalidAddress 表示 IP 过滤规则中的地址既不是 IP 也不是 CIDR。
+	ErrIPFilterInvalidAddress = errors.New("ip filter address is invalid")
+	// ErrIdempotencyLeaseLost 表示幂等处理中记录的租约已过期或已被其它请求重新占用。
+	ErrIdempotencyLeaseLost = errors.New("idempotency lease lost")
+)
+
 func ValidationErrorToInvalidArgument() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		resp, err := handler(ctx, req)
@@ -0,0 +1,119 @@
+package gm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// defaultInternalMessage 是未配置本地化文案时未知错误的对外消息。
+const defaultInternalMessage = "internal error"
+
+// ErrorMappingOptions 定义错误映射拦截器配置。
+type ErrorMappingOptions struct {
+	// Localizer 用于把消息 key 转换为本地化文案；为空或找不到文案时使用消息 key 本身。
+	Localizer rpcerror.Localizer
+	// ExposeUnknownErrors 为 true 时未知错误保留原始消息，只建议在 dev 环境开启。
+	ExposeUnknownErrors bool
+}
+
+// NewErrorMappingInterceptor 创建错误映射 unary 拦截器。
+//
+// 映射规则：
+//   - 实现 rpcerror.AppError 的业务错误按 x-firefly-app-language 本地化，
+//     并附带 ErrorInfo{reason=消息 key} 与 LocalizedMessage details；即使其内部包装了下游 status 错误；
+//   - 其它已经是 gRPC status 的错误原样返回；
+//   - context 取消/超时转换为 Canceled / DeadlineExceeded；
+//   - 其它原始 Go 错误统一转换为 Internal，默认不暴露原始错误字符串。
+//
+// 应放在访问日志之内，使访问日志记录映射后的状态码。
+func NewErrorMappingInterceptor(options ErrorMappingOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, mapError(ctx, err, options)
+	}
+}
+
+// NewErrorMappingStreamInterceptor 是 NewErrorMappingInterceptor 的 stream 版本。
+func NewErrorMappingStreamInterceptor(options ErrorMappingOptions) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		return mapError(ss.Context(), err, options)
+	}
+}
+
+// mapError 把 handler 返回的错误转换为对外的 gRPC status。
+//
+// 业务错误优先于 status 判断：AppError 包装下游 status 错误时按业务错误映射，
+// 避免下游 code 与携带内部地址等信息的原始消息透传给调用方。
+func mapError(ctx context.Context, err error, options ErrorMappingOptions) error {
+	var appErr rpcerror.AppError
+	if errors.As(err, &appErr) {
+		return localizedStatus(ctx, appErr.GRPCCode(), appErr.MessageKey(), appErr.MessageArgs(), options)
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if options.ExposeUnknownErrors {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return localizedStatus(ctx, codes.Internal, rpcerror.MessageKeyInternal, nil, options)
+}
+
+// localizedStatus 生成带消息 key 与本地化文案 details 的 status。
+func localizedStatus(ctx context.Context, code codes.Code, key string, args []any, options ErrorMappingOptions) error {
+	language := requestLanguage(ctx)
+	message, locale := key, language
+	if options.Localizer != nil {
+		if localized, resolved, ok := options.Localizer.Localize(language, key, args...); ok {
+			message, locale = localized, resolved
+		}
+	}
+	if message == rpcerror.MessageKeyInternal {
+		message = defaultInternalMessage
+	}
+
+	st := status.New(code, message)
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   key,
+			Domain:   rpcerror.Domain,
+			Metadata: map[string]string{rpcerror.MetadataMessageKey: key},
+		},
+	}
+	if locale != "" {
+		details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: message})
+	}
+	withDetails, detailErr := st.WithDetails(details...)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// requestLanguage 优先读取 service.Context，缺失时回退到入站 metadata。
+func requestLanguage(ctx context.Context) string {
+	if serviceContext, ok := service.FromContext(ctx); ok && serviceContext.AppLanguage != "" {
+		return serviceContext.AppLanguage
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return parseLogMetaKey(md, constant.AppLanguage)
+}
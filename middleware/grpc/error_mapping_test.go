@@ -0,0 +1,81 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewErrorMappingInterceptor(t *testing.T) {
+	interceptor := NewErrorMappingInterceptor(ErrorMappingOptions{
+		Localizer: &rpcerror.Catalog{
+			Messages: map[string]map[string]string{
+				"zh": {"order.not_found": "订单 %s 不存在", rpcerror.MessageKeyInternal: "服务内部错误"},
+			},
+		},
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.AppLanguage, "zh-CN"))
+	call := func(handlerErr error) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+			func(ctx context.Context, req any) (any, error) { return nil, handlerErr })
+		return err
+	}
+
+	err := call(rpcerror.Wrap(errors.New("sql: no rows"), codes.NotFound, "order.not_found", "o-1"))
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound || st.Message() != "订单 o-1 不存在" {
+		t.Fatalf("unexpected mapped app error: %v", err)
+	}
+	var (
+		reason string
+		locale string
+	)
+	for _, detail := range st.Details() {
+		switch value := detail.(type) {
+		case *errdetails.ErrorInfo:
+			reason = value.GetReason()
+		case *errdetails.LocalizedMessage:
+			locale = value.GetLocale()
+		}
+	}
+	if reason != "order.not_found" || locale != "zh" {
+		t.Fatalf("unexpected details: reason=%q locale=%q", reason, locale)
+	}
+
+	// 业务错误包装下游 status 时按业务错误映射，不透传下游 code 与内部地址。
+	err = call(rpcerror.Wrap(status.Error(codes.Unavailable, "db-host-10.0.0.5:5432 refused"), codes.NotFound, "order.not_found", "o-2"))
+	if st := status.Convert(err); st.Code() != codes.NotFound || st.Message() != "订单 o-2 不存在" {
+		t.Fatalf("expected wrapped status to map as app error, got %v", err)
+	}
+
+	err = call(errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	if st := status.Convert(err); st.Code() != codes.Internal || st.Message() != "服务内部错误" {
+		t.Fatalf("expected raw error to be hidden, got %v", err)
+	}
+
+	original := status.Error(codes.PermissionDenied, "denied")
+	if err := call(original); err != original {
+		t.Fatalf("expected status error to pass through, got %v", err)
+	}
+
+	if err := call(context.DeadlineExceeded); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected context error to map to DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNewErrorMappingInterceptorWithoutLocalizer(t *testing.T) {
+	interceptor := NewErrorMappingInterceptor(ErrorMappingOptions{})
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) { return nil, errors.New("boom") })
+	if st := status.Convert(err); st.Code() != codes.Internal || st.Message() != "internal error" {
+		t.Fatalf("unexpected mapped error: %v", err)
+	}
+}
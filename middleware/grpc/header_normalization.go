@@ -0,0 +1,187 @@
+package gm
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationHeader 是标准 Authorization header 在 gRPC metadata 中的小写 key。
+const authorizationHeader = "authorization"
+
+// HeaderNormalizationOptions 定义入口 header 规范化配置。
+type HeaderNormalizationOptions struct {
+	// TrustedProxies 表示可信网关/代理的 IP 或 CIDR。只有对端属于可信代理时才采信
+	// x-real-ip / x-forwarded-for；否则丢弃这两个 header，并以对端地址写入 x-real-ip。
+	TrustedProxies []string
+	// MapAuthorization 表示把经由可信网关的 authorization: Bearer <token> 映射为 x-firefly-user-authority，
+	// 仅在后者缺失时生效，便于外部客户端沿用标准 header。
+	MapAuthorization bool
+	// RejectUnknown 表示遇到 current 协议未定义的 x-firefly-* key 时返回 InvalidArgument。
+	RejectUnknown bool
+	// ShadowPeers 表示除 TrustedProxies 外允许携带 x-firefly-shadow 的对端 IP 或 CIDR，
+	// 通常为挂载 NewMirrorInterceptor 的内部服务网段。其它对端携带的镜像标记会被丢弃。
+	ShadowPeers []string
+}
+
+// headerNormalizer 是预解析后的规范化配置。
+type headerNormalizer struct {
+	trusted     []netip.Prefix
+	shadowPeers []netip.Prefix
+	options     HeaderNormalizationOptions
+}
+
+func newHeaderNormalizer(options HeaderNormalizationOptions) (*headerNormalizer, error) {
+	trusted, err := parsePrefixes(options.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	shadowPeers, err := parsePrefixes(options.ShadowPeers)
+	if err != nil {
+		return nil, err
+	}
+	return &headerNormalizer{trusted: trusted, shadowPeers: shadowPeers, options: options}, nil
+}
+
+// normalize 返回携带规范化 metadata 的 ctx；原始入站 metadata 不会被修改。
+func (n *headerNormalizer) normalize(ctx context.Context) (context.Context, error) {
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	md := incoming.Copy()
+	if md == nil {
+		md = metadata.MD{}
+	}
+
+	var peerAddr netip.Addr
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr, _ = parseAddr(p.Addr.String())
+	}
+	trusted := peerAddr.IsValid() && containsAddr(n.trusted, peerAddr)
+
+	// 旧 key 只在经由可信网关时映射；外部直连携带的旧身份 key 直接丢弃，避免绕过网关伪造身份。
+	if !trusted {
+		for key := range md {
+			if _, ok := constant.LegacyHeaderAliases[strings.ToLower(strings.TrimSpace(key))]; ok {
+				delete(md, key)
+			}
+		}
+	}
+
+	// 统一小写并把迁移期旧 key 映射到 current key。
+	if err := constant.Canonicalize(md, constant.CanonicalizeOptions{RejectUnknown: n.options.RejectUnknown}); err != nil {
+		return ctx, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	normalizeClientIp(md, peerAddr, trusted, n.trusted)
+
+	// Authorization 同样只在经由可信网关时映射为用户 authority。
+	if trusted && n.options.MapAuthorization && len(md.Get(constant.UserAuthority)) == 0 {
+		if token := bearerToken(parseLogMetaKey(md, authorizationHeader)); token != "" {
+			md.Set(constant.UserAuthority, token)
+		}
+	}
+
+	// 镜像标记会让整条调用链跳过副作用，只采信可信网关或镜像来源对端携带的标记。
+	if parseLogMetaKey(md, constant.Shadow) == "1" &&
+		(trusted || (peerAddr.IsValid() && containsAddr(n.shadowPeers, peerAddr))) {
+		ctx = service.WithShadow(ctx)
+	} else {
+		md.Delete(constant.Shadow)
+	}
+
+	return metadata.NewIncomingContext(ctx, md), nil
+}
+
+// normalizeClientIp 只在请求经由可信代理时采信客户端 IP header，避免外部直连伪造来源。
+func normalizeClientIp(md metadata.MD, peerAddr netip.Addr, trusted bool, proxies []netip.Prefix) {
+	if trusted {
+		// 网关只写了 x-forwarded-for 时，从链路中解析原始客户端 IP。
+		if parseLogMetaKey(md, constant.XRealIp) == "" {
+			if addr, ok := forwardedClientAddr(md.Get(constant.XForwardedFor), proxies); ok {
+				md.Set(constant.XRealIp, addr.String())
+			}
+		}
+		return
+	}
+
+	md.Delete(constant.XRealIp)
+	md.Delete(constant.XForwardedFor)
+	if peerAddr.IsValid() {
+		md.Set(constant.XRealIp, peerAddr.String())
+	}
+}
+
+// forwardedClientAddr 从右向左遍历 x-forwarded-for，跳过可信代理，返回第一个非可信地址。
+//
+// 最左侧的地址由客户端自行填写、可以伪造，只有每一跳可信代理追加在右侧的地址才可信；
+// 遇到无法解析的地址时停止，链路全部由可信代理组成时取最左侧的可信地址。
+func forwardedClientAddr(values []string, proxies []netip.Prefix) (netip.Addr, bool) {
+	var hops []string
+	for _, value := range values {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseAddr(hops[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !containsAddr(proxies, addr) {
+			break
+		}
+	}
+	return client, client.IsValid()
+}
+
+// bearerToken 从 Authorization 值中取出 Bearer token；其它认证方案返回空字符串。
+func bearerToken(value string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// NewHeaderNormalizationUnaryInterceptor 创建入口 header 规范化 unary 拦截器。
+//
+// 它把外部或旧版本 header 统一为 current 协议 key：经由可信网关时旧 x-firefly-* 别名映射到 current key，
+// 否则丢弃；客户端 IP 同样只在经由可信网关时采信，可选地把 Authorization 映射为用户 authority。
+// x-firefly-shadow 只在对端属于 TrustedProxies 或 ShadowPeers 时采信并写入 service.WithShadow，否则丢弃。
+// 应放在链路最前面，使后续的服务上下文、访问日志、IP 过滤等读取同一份规范化 metadata。
+// TrustedProxies 中的地址非法时返回 ErrIPFilterInvalidAddress。
+func NewHeaderNormalizationUnaryInterceptor(options HeaderNormalizationOptions) (grpc.UnaryServerInterceptor, error) {
+	normalizer, err := newHeaderNormalizer(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := normalizer.normalize(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}, nil
+}
+
+// NewHeaderNormalizationStreamInterceptor 是 NewHeaderNormalizationUnaryInterceptor 的 stream 版本。
+func NewHeaderNormalizationStreamInterceptor(options HeaderNormalizationOptions) (grpc.StreamServerInterceptor, error) {
+	normalizer, err := newHeaderNormalizer(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := normalizer.normalize(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}, nil
+}
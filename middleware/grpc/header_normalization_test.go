@@ -0,0 +1,139 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestHeaderNormalizationUnaryInterceptor(t *testing.T) {
+	interceptor, err := NewHeaderNormalizationUnaryInterceptor(HeaderNormalizationOptions{
+		TrustedProxies:   []string{"10.0.0.0/8"},
+		MapAuthorization: true,
+	})
+	if err != nil {
+		t.Fatalf("new interceptor: %v", err)
+	}
+
+	normalized := func(peerIp string, md metadata.MD) metadata.MD {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(peerIp), Port: 5000}})
+		ctx = metadata.NewIncomingContext(ctx, md)
+		var got metadata.MD
+		if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"}, func(ctx context.Context, req any) (any, error) {
+			got, _ = metadata.FromIncomingContext(ctx)
+			return nil, nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+
+	// 经由可信网关：采信 x-forwarded-for 第一个地址，并映射旧 key 与 Authorization。
+	got := normalized("10.1.2.3", metadata.MD{
+		constant.XForwardedFor:                {"203.0.113.7, 10.1.2.3"},
+		constant.HeaderPrefix + "app-user-id": {"u1"},
+		authorizationHeader:                   {"Bearer token-1"},
+	})
+	if firstValue(got.Get(constant.XRealIp)) != "203.0.113.7" {
+		t.Fatalf("expected client ip from x-forwarded-for, got %v", got)
+	}
+	if firstValue(got.Get(constant.UserId)) != "u1" {
+		t.Fatalf("expected legacy user id to be mapped, got %v", got)
+	}
+	if firstValue(got.Get(constant.UserAuthority)) != "token-1" {
+		t.Fatalf("expected authorization to be mapped, got %v", got)
+	}
+
+	// 客户端自行在 x-forwarded-for 最左侧填写的地址不被采信，取可信代理追加的最右侧非代理地址。
+	got = normalized("10.1.2.3", metadata.MD{
+		constant.XForwardedFor: {"1.1.1.1, 203.0.113.7", "10.0.0.5"},
+	})
+	if firstValue(got.Get(constant.XRealIp)) != "203.0.113.7" {
+		t.Fatalf("expected client ip appended by trusted hop, got %v", got)
+	}
+
+	// 外部直连：丢弃伪造的 IP header，改用对端地址。
+	got = normalized("198.51.100.9", metadata.MD{
+		constant.XRealIp:       {"1.1.1.1"},
+		constant.XForwardedFor: {"1.1.1.1"},
+	})
+	if firstValue(got.Get(constant.XRealIp)) != "198.51.100.9" || len(got.Get(constant.XForwardedFor)) != 0 {
+		t.Fatalf("expected spoofed ip headers to be replaced, got %v", got)
+	}
+
+	// 外部直连携带旧身份 key 与 Authorization 时不映射，旧 key 直接丢弃。
+	got = normalized("198.51.100.9", metadata.MD{
+		constant.HeaderPrefix + "app-user-id":   {"admin"},
+		constant.HeaderPrefix + "app-tenant-id": {"t1"},
+		authorizationHeader:                     {"Bearer forged"},
+	})
+	if len(got.Get(constant.UserId)) != 0 || len(got.Get(constant.TenantId)) != 0 || len(got.Get(constant.HeaderPrefix+"app-user-id")) != 0 {
+		t.Fatalf("expected legacy identity keys from untrusted peer to be dropped, got %v", got)
+	}
+	if len(got.Get(constant.UserAuthority)) != 0 {
+		t.Fatalf("expected authorization from untrusted peer not to be mapped, got %v", got)
+	}
+}
+
+func TestHeaderNormalizationOnlyTrustsShadowFromKnownPeers(t *testing.T) {
+	interceptor, err := NewHeaderNormalizationUnaryInterceptor(HeaderNormalizationOptions{
+		TrustedProxies: []string{"10.0.0.0/8"},
+		ShadowPeers:    []string{"172.16.0.0/12"},
+	})
+	if err != nil {
+		t.Fatalf("new interceptor: %v", err)
+	}
+
+	shadow := func(peerIp string) (bool, metadata.MD) {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(peerIp), Port: 5000}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constant.Shadow, "1"))
+		var (
+			isShadow bool
+			got      metadata.MD
+		)
+		if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Charge"}, func(ctx context.Context, req any) (any, error) {
+			isShadow = IsShadowRequest(ctx)
+			got, _ = metadata.FromIncomingContext(ctx)
+			return nil, nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return isShadow, got
+	}
+
+	for _, peerIp := range []string{"10.1.2.3", "172.16.0.8"} {
+		if isShadow, _ := shadow(peerIp); !isShadow {
+			t.Fatalf("expected shadow flag from %s to be trusted", peerIp)
+		}
+	}
+	// 外部调用方伪造镜像标记：丢弃 header，handler 不会跳过副作用。
+	isShadow, got := shadow("198.51.100.9")
+	if isShadow || len(got.Get(constant.Shadow)) != 0 {
+		t.Fatalf("expected untrusted shadow flag to be dropped, got shadow=%v md=%v", isShadow, got)
+	}
+}
+
+func TestHeaderNormalizationRejectsUnknownHeader(t *testing.T) {
+	interceptor, err := NewHeaderNormalizationStreamInterceptor(HeaderNormalizationOptions{RejectUnknown: true})
+	if err != nil {
+		t.Fatalf("new interceptor: %v", err)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.HeaderPrefix+"unknown", "1"))
+	err = interceptor(nil, &countingServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"},
+		func(srv any, stream grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	if _, err := NewHeaderNormalizationUnaryInterceptor(HeaderNormalizationOptions{TrustedProxies: []string{"gateway"}}); !errors.Is(err, ErrIPFilterInvalidAddress) {
+		t.Fatalf("expected invalid address error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package gm
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultHedgingDelay 是发出下一次对冲请求前的默认等待时长。
+	DefaultHedgingDelay = 100 * time.Millisecond
+	// DefaultHedgingMaxAttempts 是包含首次请求在内的默认最大请求次数。
+	DefaultHedgingMaxAttempts = 2
+	// maxHedgingAttempts 是最大请求次数上限，避免配置失误放大下游流量。
+	maxHedgingAttempts = 5
+)
+
+// HedgingOptions 定义对冲请求客户端拦截器配置。
+type HedgingOptions struct {
+	// Methods 表示允许对冲的 full method，只应包含幂等读接口；为空时拦截器不做任何对冲。
+	Methods []string
+	// Delay 表示上一次请求未返回时，等待多久发出下一次对冲请求；未设置时使用 DefaultHedgingDelay。
+	Delay time.Duration
+	// MaxAttempts 表示包含首次请求在内的最大请求次数；未设置时使用 DefaultHedgingMaxAttempts，上限为 5。
+	MaxAttempts int
+	// NonFatalCodes 表示不终止对冲的错误码，出现时立即补发下一次请求；未设置时仅 Unavailable。
+	NonFatalCodes []codes.Code
+}
+
+// normalize 补齐默认值。
+func (o HedgingOptions) normalize() HedgingOptions {
+	if o.Delay <= 0 {
+		o.Delay = DefaultHedgingDelay
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultHedgingMaxAttempts
+	}
+	if o.MaxAttempts > maxHedgingAttempts {
+		o.MaxAttempts = maxHedgingAttempts
+	}
+	if len(o.NonFatalCodes) == 0 {
+		o.NonFatalCodes = []codes.Code{codes.Unavailable}
+	}
+	return o
+}
+
+// hedgingResult 表示一次对冲请求的结果。
+type hedgingResult struct {
+	reply proto.Message
+	err   error
+}
+
+// NewHedgingInterceptor 创建对冲请求 unary 客户端拦截器，用于压低关键读链路的尾延迟。
+//
+// 首次请求超过 Delay 仍未返回时并发发出下一次请求，最先成功的结果胜出，其余请求被取消。
+// 连接上使用 round_robin 等多子连接负载均衡时，对冲请求会落到不同后端。
+//
+// 每次请求使用独立的响应对象，胜出后再合并到调用方的 reply，因此 reply 必须是 proto.Message；
+// 对冲方法不应同时使用 grpc.Header / grpc.Trailer 等会被并发写入的 CallOption。
+func NewHedgingInterceptor(options HedgingOptions) grpc.UnaryClientInterceptor {
+	options = options.normalize()
+
+	methods := make(map[string]struct{}, len(options.Methods))
+	for _, method := range options.Methods {
+		if method != "" {
+			methods[method] = struct{}{}
+		}
+	}
+	nonFatal := make(map[codes.Code]struct{}, len(options.NonFatalCodes))
+	for _, code := range options.NonFatalCodes {
+		nonFatal[code] = struct{}{}
+	}
+	hedges, _ := otel.Meter(pkgName).Int64Counter("rpc.client.hedged_attempts",
+		metric.WithDescription("Number of hedged attempts issued after the first attempt."),
+	)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		message, ok := reply.(proto.Message)
+		if _, allowed := methods[method]; !allowed || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// 胜出后取消其余在途请求。
+		hedgeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan hedgingResult, options.MaxAttempts)
+		started, pending := 0, 0
+		launch := func() {
+			attemptReply := message.ProtoReflect().New().Interface()
+			if started > 0 {
+				hedges.Add(ctx, 1, metric.WithAttributes(attribute.String(metricAttrRPCMethod, method)))
+			}
+			started++
+			pending++
+			go func() {
+				err := invoker(hedgeCtx, method, req, attemptReply, cc, opts...)
+				results <- hedgingResult{reply: attemptReply, err: err}
+			}()
+		}
+
+		launch()
+		timer := time.NewTimer(options.Delay)
+		defer timer.Stop()
+
+		var lastErr error
+		for pending > 0 {
+			select {
+			case <-timer.C:
+				if started < options.MaxAttempts {
+					launch()
+					timer.Reset(options.Delay)
+				}
+			case result := <-results:
+				pending--
+				if result.err == nil {
+					proto.Reset(message)
+					proto.Merge(message, result.reply)
+					return nil
+				}
+				lastErr = result.err
+				if _, ok := nonFatal[status.Code(result.err)]; !ok {
+					return result.err
+				}
+				// 非致命错误不必等待 Delay，立即补发下一次请求。
+				if started < options.MaxAttempts {
+					launch()
+					timer.Reset(options.Delay)
+				}
+			}
+		}
+		return lastErr
+	}
+}
@@ -0,0 +1,87 @@
+package gm
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewHedgingInterceptorReturnsFastestAttempt(t *testing.T) {
+	interceptor := NewHedgingInterceptor(HedgingOptions{
+		Methods: []string{"/example.Service/Get"},
+		Delay:   10 * time.Millisecond,
+	})
+
+	var calls atomic.Int32
+	var cancelled atomic.Bool
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		// 首次请求卡住，直到被胜出的对冲请求取消。
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			cancelled.Store(true)
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		reply.(*wrapperspb.StringValue).Value = "hedged"
+		return nil
+	}
+
+	reply := &wrapperspb.StringValue{}
+	if err := interceptor(context.Background(), "/example.Service/Get", nil, reply, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply.Value != "hedged" || calls.Load() != 2 {
+		t.Fatalf("unexpected hedging result: reply=%q calls=%d", reply.Value, calls.Load())
+	}
+	deadline := time.Now().Add(time.Second)
+	for !cancelled.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !cancelled.Load() {
+		t.Fatalf("expected losing attempt to be cancelled")
+	}
+}
+
+func TestNewHedgingInterceptorStopsOnFatalError(t *testing.T) {
+	interceptor := NewHedgingInterceptor(HedgingOptions{
+		Methods:     []string{"/example.Service/Get"},
+		Delay:       time.Hour,
+		MaxAttempts: 3,
+	})
+
+	var calls atomic.Int32
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		// Unavailable 立即触发下一次请求，NotFound 直接返回。
+		if calls.Add(1) == 1 {
+			return status.Error(codes.Unavailable, "down")
+		}
+		return status.Error(codes.NotFound, "missing")
+	}
+
+	err := interceptor(context.Background(), "/example.Service/Get", nil, &wrapperspb.StringValue{}, nil, invoker)
+	if status.Code(err) != codes.NotFound || calls.Load() != 2 {
+		t.Fatalf("unexpected result: err=%v calls=%d", err, calls.Load())
+	}
+}
+
+func TestNewHedgingInterceptorSkipsUnlistedMethods(t *testing.T) {
+	interceptor := NewHedgingInterceptor(HedgingOptions{Delay: time.Millisecond})
+
+	var calls atomic.Int32
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+	if err := interceptor(context.Background(), "/example.Service/Create", nil, &wrapperspb.StringValue{}, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected non-hedged method to be called once, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,269 @@
+package gm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// DefaultIdempotencyWindow 是幂等记录的默认保留时长。
+	DefaultIdempotencyWindow = 24 * time.Hour
+	// DefaultIdempotencyLockTimeout 是处理中记录的默认占用时长，超时后允许重新执行，避免进程崩溃导致永久锁定。
+	DefaultIdempotencyLockTimeout = time.Minute
+)
+
+// IdempotencyRecord 表示某个幂等键的处理状态。
+type IdempotencyRecord struct {
+	// RequestHash 表示首个请求的报文摘要，用于识别同一幂等键被不同请求复用。
+	RequestHash string `json:"request_hash"`
+	// Done 表示首个请求是否已经成功完成。
+	Done bool `json:"done"`
+	// ResponseType 表示响应 proto 消息的完整名称。
+	ResponseType string `json:"response_type,omitempty"`
+	// Response 表示序列化后的响应。
+	Response []byte `json:"response,omitempty"`
+}
+
+// IdempotencyStore 定义幂等记录存储，单实例可使用 NewMemoryIdempotencyStore，
+// 多实例时可基于 Redis（SET NX PX 写入租约令牌，Lua 脚本比较令牌后更新或删除）实现。
+type IdempotencyStore interface {
+	// Acquire 原子地占用 key：key 不存在时写入处理中记录并返回新的租约令牌与 acquired=true；否则返回已有记录。
+	Acquire(ctx context.Context, key string, requestHash string, lockTimeout time.Duration) (record IdempotencyRecord, token string, acquired bool, err error)
+	// Complete 在 token 仍持有处理中记录时写入成功结果，并把记录保留 window 时长；
+	// 租约已过期或被其它请求重新占用时返回 ErrIdempotencyLeaseLost，不覆盖新持有者的记录。
+	Complete(ctx context.Context, key string, token string, record IdempotencyRecord, window time.Duration) error
+	// Release 在 token 仍持有处理中记录时删除记录，使失败的请求可以用同一 key 重试；
+	// 租约已过期或被其它请求重新占用时返回 ErrIdempotencyLeaseLost。
+	Release(ctx context.Context, key string, token string) error
+}
+
+// IdempotencyOptions 定义服务端幂等去重配置。
+type IdempotencyOptions struct {
+	// Store 表示幂等记录存储；为空时使用进程内存储。
+	Store IdempotencyStore
+	// Window 表示成功结果的保留时长；为 0 时使用 DefaultIdempotencyWindow。
+	Window time.Duration
+	// LockTimeout 表示处理中记录的占用时长；为 0 时使用 DefaultIdempotencyLockTimeout。
+	LockTimeout time.Duration
+}
+
+func (o IdempotencyOptions) normalize() IdempotencyOptions {
+	if o.Store == nil {
+		o.Store = NewMemoryIdempotencyStore()
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultIdempotencyWindow
+	}
+	if o.LockTimeout <= 0 {
+		o.LockTimeout = DefaultIdempotencyLockTimeout
+	}
+	return o
+}
+
+// NewIdempotencyInterceptor 创建幂等键去重 unary 拦截器。
+//
+// 携带 x-firefly-idempotency-key 的请求按 方法 + 租户 + 认证主体 + 幂等键 去重，
+// 不同用户复用同一个 key 不会拿到彼此的响应：
+// - 首个请求正常执行，成功响应写入存储；失败时释放记录，允许调用方用同一 key 重试；
+// - 窗口内的重复请求直接重放首个成功响应，不再执行 handler；
+// - 首个请求仍在处理中时返回可重试的 Aborted；
+// - 同一 key 携带不同请求报文时返回 InvalidArgument。
+// 存储不可用时回退为直接执行 handler。
+func NewIdempotencyInterceptor(options IdempotencyOptions) grpc.UnaryServerInterceptor {
+	options = options.normalize()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		idempotencyKey := parseLogMetaKey(md, constant.IdempotencyKey)
+		message, ok := req.(proto.Message)
+		if idempotencyKey == "" || !ok {
+			return handler(ctx, req)
+		}
+		requestHash, err := idempotencyRequestHash(message)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		method := unaryFullMethod(info)
+		key := method + "|" + tenantIdFromContext(ctx) + "|" + subjectFromContext(ctx) + "|" + idempotencyKey
+		record, token, acquired, err := options.Store.Acquire(ctx, key, requestHash, options.LockTimeout)
+		if err != nil {
+			return handler(ctx, req)
+		}
+		if !acquired {
+			return replayIdempotentResponse(record, requestHash)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			_ = options.Store.Release(ctx, key, token)
+			return resp, err
+		}
+		if response, ok := resp.(proto.Message); ok && response != nil {
+			if data, marshalErr := proto.Marshal(response); marshalErr == nil {
+				_ = options.Store.Complete(ctx, key, token, IdempotencyRecord{
+					RequestHash:  requestHash,
+					Done:         true,
+					ResponseType: string(response.ProtoReflect().Descriptor().FullName()),
+					Response:     data,
+				}, options.Window)
+				return resp, nil
+			}
+		}
+		// 响应无法序列化时不保留记录，避免后续重试被永久阻塞。
+		_ = options.Store.Release(ctx, key, token)
+		return resp, nil
+	}
+}
+
+// replayIdempotentResponse 根据已有记录返回重放响应或冲突错误。
+func replayIdempotentResponse(record IdempotencyRecord, requestHash string) (any, error) {
+	if record.RequestHash != requestHash {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key reused with a different request")
+	}
+	if !record.Done {
+		return nil, rpcerror.WithRetryHint(
+			status.Error(codes.Aborted, "request with the same idempotency key is in progress"),
+			rpcerror.RetryHint{Retryable: true},
+		)
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.ResponseType))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "idempotent response type is not registered")
+	}
+	resp := messageType.New().Interface()
+	if err := proto.Unmarshal(record.Response, resp); err != nil {
+		return nil, status.Error(codes.Internal, "idempotent response is corrupted")
+	}
+	return resp, nil
+}
+
+// idempotencyRequestHash 使用确定性序列化计算请求摘要。
+func idempotencyRequestHash(req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// IdempotencyKeyOptions 定义客户端幂等键生成配置。
+type IdempotencyKeyOptions struct {
+	// WritePrefixes 表示需要自动生成幂等键的方法短名前缀；为空时使用 DefaultAuditWritePrefixes。
+	WritePrefixes []string
+	// Methods 表示额外需要生成幂等键的完整方法名。
+	Methods []string
+}
+
+// NewIdempotencyKeyInterceptor 创建客户端幂等键 unary 拦截器，为写方法生成 x-firefly-idempotency-key。
+//
+// 应放在 NewRetryInterceptor 之外，使同一次逻辑调用的全部重试共享同一个 key；
+// 调用方已在出站 metadata 中设置 key 时保持不变。
+func NewIdempotencyKeyInterceptor(options IdempotencyKeyOptions) grpc.UnaryClientInterceptor {
+	prefixes := options.WritePrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultAuditWritePrefixes
+	}
+	matcher := &auditMatcher{prefixes: prefixes, methods: buildMethodSet(options.Methods)}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if matcher.matches(method) {
+			if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(constant.IdempotencyKey)) == 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, constant.IdempotencyKey, uuid.NewString())
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// memoryIdempotencyEntry 是进程内幂等记录。
+type memoryIdempotencyEntry struct {
+	record IdempotencyRecord
+	// token 表示处理中记录的租约令牌；完成后的记录为空。
+	token    string
+	expireAt time.Time
+}
+
+// MemoryIdempotencyStore 是进程内幂等记录存储。
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	now     func() time.Time
+	// lastSweep 记录最近一次清理过期记录的时间。
+	lastSweep time.Time
+}
+
+// NewMemoryIdempotencyStore 创建进程内幂等记录存储。
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]memoryIdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Acquire 实现 IdempotencyStore。
+func (s *MemoryIdempotencyStore) Acquire(_ context.Context, key string, requestHash string, lockTimeout time.Duration) (IdempotencyRecord, string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastSweep) >= lockTimeout {
+		for existing, entry := range s.entries {
+			if !now.Before(entry.expireAt) {
+				delete(s.entries, existing)
+			}
+		}
+		s.lastSweep = now
+	}
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expireAt) {
+		return entry.record, "", false, nil
+	}
+	token := uuid.NewString()
+	s.entries[key] = memoryIdempotencyEntry{
+		record:   IdempotencyRecord{RequestHash: requestHash},
+		token:    token,
+		expireAt: now.Add(lockTimeout),
+	}
+	return IdempotencyRecord{}, token, true, nil
+}
+
+// Complete 实现 IdempotencyStore。
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, token string, record IdempotencyRecord, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if !s.holds(key, token, now) {
+		return ErrIdempotencyLeaseLost
+	}
+	s.entries[key] = memoryIdempotencyEntry{record: record, expireAt: now.Add(window)}
+	return nil
+}
+
+// Release 实现 IdempotencyStore。
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.holds(key, token, s.now()) {
+		return ErrIdempotencyLeaseLost
+	}
+	delete(s.entries, key)
+	return nil
+}
+
+// holds 判断 token 是否仍持有未过期的处理中记录；调用方需持有 s.mu。
+func (s *MemoryIdempotencyStore) holds(key string, token string, now time.Time) bool {
+	entry, ok := s.entries[key]
+	return ok && token != "" && entry.token == token && now.Before(entry.expireAt)
+}
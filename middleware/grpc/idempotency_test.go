@@ -0,0 +1,160 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewIdempotencyInterceptorReplaysFirstResponse(t *testing.T) {
+	interceptor := NewIdempotencyInterceptor(IdempotencyOptions{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/acme.pay.v1.PayService/CreatePayment"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.IdempotencyKey, "key-1"))
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.Unavailable, "db down")
+		}
+		return wrapperspb.Int64(int64(calls)), nil
+	}
+
+	if _, err := interceptor(ctx, wrapperspb.String("order-1"), info, handler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected first failure to pass through, got %v", err)
+	}
+	first, err := interceptor(ctx, wrapperspb.String("order-1"), info, handler)
+	if err != nil {
+		t.Fatalf("expected retry after failure to execute, got %v", err)
+	}
+	replayed, err := interceptor(ctx, wrapperspb.String("order-1"), info, handler)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if calls != 2 || !proto.Equal(first.(proto.Message), replayed.(proto.Message)) {
+		t.Fatalf("expected replay of first success, calls=%d first=%v replayed=%v", calls, first, replayed)
+	}
+
+	if _, err := interceptor(ctx, wrapperspb.String("order-2"), info, handler); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected key reuse with different request to fail, got %v", err)
+	}
+	if _, err := interceptor(context.Background(), wrapperspb.String("order-1"), info, handler); err != nil || calls != 3 {
+		t.Fatalf("expected requests without key to bypass dedup, calls=%d err=%v", calls, err)
+	}
+}
+
+func TestNewIdempotencyInterceptorRejectsInProgress(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	interceptor := NewIdempotencyInterceptor(IdempotencyOptions{Store: store})
+	info := &grpc.UnaryServerInfo{FullMethod: "/acme.pay.v1.PayService/CreatePayment"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.IdempotencyKey, "key-1"))
+
+	_, err := interceptor(ctx, wrapperspb.String("order-1"), info, func(ctx context.Context, req any) (any, error) {
+		_, nestedErr := interceptor(ctx, wrapperspb.String("order-1"), info, func(ctx context.Context, req any) (any, error) {
+			t.Fatal("expected duplicate in-flight request not to execute")
+			return nil, nil
+		})
+		if status.Code(nestedErr) != codes.Aborted {
+			t.Fatalf("expected Aborted for in-flight duplicate, got %v", nestedErr)
+		}
+		return wrapperspb.String("ok"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewIdempotencyInterceptorIsolatesSubjects(t *testing.T) {
+	interceptor := NewIdempotencyInterceptor(IdempotencyOptions{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/acme.pay.v1.PayService/CreatePayment"}
+	userCtx := func(userId string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			constant.IdempotencyKey, "key-1",
+			constant.TenantId, "tenant-1",
+			constant.UserId, userId,
+		))
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return wrapperspb.String(subjectFromContext(ctx)), nil
+	}
+
+	// 同租户不同用户复用同一个 key 时各自执行，不会拿到对方的响应。
+	for _, userId := range []string{"u1", "u2"} {
+		resp, err := interceptor(userCtx(userId), wrapperspb.String("order-1"), info, handler)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := resp.(*wrapperspb.StringValue).GetValue(); got != "user:"+userId {
+			t.Fatalf("expected response for %s, got %q", userId, got)
+		}
+	}
+}
+
+func TestMemoryIdempotencyStoreRequiresLeaseToken(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	now := time.Unix(1710000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, stale, acquired, err := store.Acquire(ctx, "k", "hash", time.Minute)
+	if err != nil || !acquired {
+		t.Fatalf("expected first acquire to succeed, acquired=%v err=%v", acquired, err)
+	}
+	// 首个请求超过 LockTimeout 后，第二个请求重新占用。
+	now = now.Add(2 * time.Minute)
+	_, current, acquired, err := store.Acquire(ctx, "k", "hash", time.Minute)
+	if err != nil || !acquired || current == stale {
+		t.Fatalf("expected re-acquire with a new token, acquired=%v err=%v", acquired, err)
+	}
+
+	// 过期的持有者不能释放或覆盖新持有者的记录。
+	if err := store.Release(ctx, "k", stale); !errors.Is(err, ErrIdempotencyLeaseLost) {
+		t.Fatalf("expected ErrIdempotencyLeaseLost on stale release, got %v", err)
+	}
+	if err := store.Complete(ctx, "k", stale, IdempotencyRecord{RequestHash: "hash", Done: true}, time.Hour); !errors.Is(err, ErrIdempotencyLeaseLost) {
+		t.Fatalf("expected ErrIdempotencyLeaseLost on stale complete, got %v", err)
+	}
+	if record, _, acquired, _ := store.Acquire(ctx, "k", "hash", time.Minute); acquired || record.Done {
+		t.Fatalf("expected current holder's in-progress record to survive, acquired=%v record=%+v", acquired, record)
+	}
+
+	if err := store.Complete(ctx, "k", current, IdempotencyRecord{RequestHash: "hash", Done: true}, time.Hour); err != nil {
+		t.Fatalf("expected current holder to complete, got %v", err)
+	}
+	if err := store.Release(ctx, "k", current); !errors.Is(err, ErrIdempotencyLeaseLost) {
+		t.Fatalf("expected completed record not to be released, got %v", err)
+	}
+}
+
+func TestNewIdempotencyKeyInterceptor(t *testing.T) {
+	interceptor := NewIdempotencyKeyInterceptor(IdempotencyKeyOptions{})
+	keyOf := func(ctx context.Context, method string) string {
+		var key string
+		_ = interceptor(ctx, method, nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			key = firstValue(md.Get(constant.IdempotencyKey))
+			return nil
+		})
+		return key
+	}
+
+	if key := keyOf(context.Background(), "/acme.pay.v1.PayService/CreatePayment"); key == "" {
+		t.Fatal("expected generated key for write method")
+	}
+	if key := keyOf(context.Background(), "/acme.pay.v1.PayService/GetPayment"); key != "" {
+		t.Fatalf("expected no key for read method, got %q", key)
+	}
+	explicit := metadata.AppendToOutgoingContext(context.Background(), constant.IdempotencyKey, "fixed")
+	if key := keyOf(explicit, "/acme.pay.v1.PayService/CreatePayment"); key != "fixed" {
+		t.Fatalf("expected explicit key to be kept, got %q", key)
+	}
+}
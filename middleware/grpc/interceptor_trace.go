@@ -0,0 +1,218 @@
+package gm
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// InterceptorTraceTrailer 是调试模式下写入拦截器执行摘要的 gRPC trailer key。
+const InterceptorTraceTrailer = constant.HeaderPrefix + "interceptor-trace"
+
+type interceptorTraceContextKey struct{}
+
+// NamedUnaryInterceptor 表示带名称的 unary 拦截器，名称用于执行摘要展示。
+type NamedUnaryInterceptor struct {
+	// Name 表示拦截器名称，例如 service_context、access_logger。
+	Name string
+	// Interceptor 表示实际的 unary 拦截器。
+	Interceptor grpc.UnaryServerInterceptor
+}
+
+// InterceptorTraceOptions 定义拦截器执行追踪的配置。
+type InterceptorTraceOptions struct {
+	// Enabled 表示是否开启追踪；建议只在 dev/test 环境开启。
+	Enabled bool
+	// DisableTrailer 表示不把执行摘要写入 trailer，只保留在访问日志中。
+	DisableTrailer bool
+}
+
+// InterceptorTraceRecord 表示单个拦截器在本次请求中的执行情况。
+type InterceptorTraceRecord struct {
+	// Name 表示拦截器名称。
+	Name string `json:"name"`
+	// Duration 表示拦截器自身耗时（微秒），已扣除下游拦截器与 handler 的耗时。
+	Duration uint64 `json:"duration"`
+	// CalledNext 表示拦截器是否继续调用了下游；false 通常意味着请求被提前拦截。
+	CalledNext bool `json:"called_next"`
+	// MetadataAdded 表示拦截器向入站 metadata 新增或改写的 key。
+	MetadataAdded []string `json:"metadata_added,omitempty"`
+	// MetadataRemoved 表示拦截器从入站 metadata 删除的 key。
+	MetadataRemoved []string `json:"metadata_removed,omitempty"`
+	// Error 表示拦截器返回的错误描述。
+	Error string `json:"error,omitempty"`
+}
+
+// interceptorTrace 保存一次请求内全部拦截器的执行记录。
+type interceptorTrace struct {
+	// mu 保护 records，业务 handler 可能并发读取。
+	mu sync.Mutex
+	// records 按拦截器进入顺序保存执行记录。
+	records []InterceptorTraceRecord
+}
+
+// begin 按进入顺序预留一条记录并返回其下标。
+func (t *interceptorTrace) begin(name string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.records = append(t.records, InterceptorTraceRecord{Name: name})
+	return len(t.records) - 1
+}
+
+// finish 回填指定记录的执行结果。
+func (t *interceptorTrace) finish(index int, record InterceptorTraceRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.records[index] = record
+}
+
+// snapshot 返回当前全部记录的副本。
+func (t *interceptorTrace) snapshot() []InterceptorTraceRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]InterceptorTraceRecord(nil), t.records...)
+}
+
+// interceptorTraceFromContext 读取当前请求的追踪记录；未开启追踪时返回 nil。
+func interceptorTraceFromContext(ctx context.Context) *interceptorTrace {
+	value, _ := ctx.Value(interceptorTraceContextKey{}).(*interceptorTrace)
+	return value
+}
+
+// InterceptorTraceFromContext 返回当前请求已经完成的拦截器执行记录，便于业务侧调试输出。
+func InterceptorTraceFromContext(ctx context.Context) []InterceptorTraceRecord {
+	trace := interceptorTraceFromContext(ctx)
+	if trace == nil {
+		return nil
+	}
+	return trace.snapshot()
+}
+
+// NewInterceptorTrace 为一组拦截器包装执行追踪，返回可直接传给 grpc.ChainUnaryInterceptor 的切片。
+//
+// 开启后会记录：
+// - 每个拦截器的执行顺序与自身耗时；
+// - 是否继续调用了下游；
+// - 对入站 metadata 的新增与删除。
+//
+// 执行摘要会写入 trailer（InterceptorTraceTrailer），并由 NewAccessLogger 输出到 interceptor_trace 字段。
+// 未开启时原样返回拦截器，不引入任何额外开销。
+func NewInterceptorTrace(options InterceptorTraceOptions, interceptors ...NamedUnaryInterceptor) []grpc.UnaryServerInterceptor {
+	result := make([]grpc.UnaryServerInterceptor, 0, len(interceptors)+1)
+	if !options.Enabled {
+		for _, item := range interceptors {
+			if item.Interceptor != nil {
+				result = append(result, item.Interceptor)
+			}
+		}
+		return result
+	}
+
+	// 第一个拦截器负责创建追踪容器，并在请求结束时写出 trailer。
+	result = append(result, newInterceptorTraceCollector(options))
+	for _, item := range interceptors {
+		if item.Interceptor == nil {
+			continue
+		}
+		result = append(result, wrapTracedUnaryInterceptor(item))
+	}
+	return result
+}
+
+// newInterceptorTraceCollector 创建负责收集和输出执行摘要的入口拦截器。
+func newInterceptorTraceCollector(options InterceptorTraceOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		trace := &interceptorTrace{}
+		ctx = context.WithValue(ctx, interceptorTraceContextKey{}, trace)
+
+		resp, err := handler(ctx, req)
+
+		if !options.DisableTrailer {
+			if summary, e := json.Marshal(trace.snapshot()); e == nil {
+				// 单元测试或非真实 gRPC 运行时下 SetTrailer 会失败，这里忽略即可。
+				_ = grpc.SetTrailer(ctx, metadata.Pairs(InterceptorTraceTrailer, string(summary)))
+			}
+		}
+		return resp, err
+	}
+}
+
+// wrapTracedUnaryInterceptor 包装单个拦截器，记录其耗时与 metadata 变更。
+func wrapTracedUnaryInterceptor(item NamedUnaryInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		trace := interceptorTraceFromContext(ctx)
+		if trace == nil {
+			return item.Interceptor(ctx, req, info, handler)
+		}
+
+		index := trace.begin(item.Name)
+		before, _ := metadata.FromIncomingContext(ctx)
+
+		var (
+			calledNext bool
+			after      metadata.MD
+			downstream time.Duration
+		)
+		start := time.Now()
+		resp, err := item.Interceptor(ctx, req, info, func(nextCtx context.Context, nextReq any) (any, error) {
+			calledNext = true
+			// 以传给下游的 ctx 为准比较 metadata，才能看到当前拦截器的改写。
+			after, _ = metadata.FromIncomingContext(nextCtx)
+			nextStart := time.Now()
+			r, e := handler(nextCtx, nextReq)
+			downstream += time.Since(nextStart)
+			return r, e
+		})
+		elapsed := time.Since(start) - downstream
+
+		record := InterceptorTraceRecord{
+			Name:       item.Name,
+			Duration:   uint64(elapsed.Microseconds()),
+			CalledNext: calledNext,
+		}
+		if calledNext {
+			record.MetadataAdded, record.MetadataRemoved = diffMetadata(before, after)
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		trace.finish(index, record)
+
+		return resp, err
+	}
+}
+
+// diffMetadata 比较两份 metadata，返回新增或改写的 key 与被删除的 key。
+func diffMetadata(before metadata.MD, after metadata.MD) ([]string, []string) {
+	var added, removed []string
+	for key, values := range after {
+		previous, ok := before[key]
+		if !ok || !equalStrings(previous, values) {
+			added = append(added, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed
+}
+
+// equalStrings 判断两个字符串切片是否逐项相等。
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
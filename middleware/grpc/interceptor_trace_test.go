@@ -0,0 +1,115 @@
+package gm
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// runUnaryChain 按 grpc.ChainUnaryInterceptor 的顺序执行一组拦截器。
+func runUnaryChain(ctx context.Context, interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if len(interceptors) == 0 {
+		return handler(ctx, nil)
+	}
+	return interceptors[0](ctx, nil, info, func(nextCtx context.Context, req any) (any, error) {
+		return runUnaryChain(nextCtx, interceptors[1:], info, handler)
+	})
+}
+
+func TestNewInterceptorTraceRecordsExecution(t *testing.T) {
+	addHeader := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		md.Set("x-added", "1")
+		delete(md, "x-removed")
+		return handler(metadata.NewIncomingContext(ctx, md), req)
+	}
+	passThrough := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ctx, req)
+	}
+
+	// inspect 位于最外层，与访问日志一样在下游全部结束后读取执行记录。
+	var records []InterceptorTraceRecord
+	inspect := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		records = InterceptorTraceFromContext(ctx)
+		return resp, err
+	}
+
+	chain := NewInterceptorTrace(InterceptorTraceOptions{Enabled: true},
+		NamedUnaryInterceptor{Name: "inspect", Interceptor: inspect},
+		NamedUnaryInterceptor{Name: "add_header", Interceptor: addHeader},
+		NamedUnaryInterceptor{Name: "pass_through", Interceptor: passThrough},
+	)
+	if len(chain) != 4 {
+		t.Fatalf("expected collector plus three interceptors, got %d", len(chain))
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-removed", "1"))
+	_, err := runUnaryChain(ctx, chain, &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 第一条记录属于 inspect 自身，读取时尚未回填。
+	records = records[1:]
+
+	if len(records) != 2 {
+		t.Fatalf("expected two records, got %+v", records)
+	}
+	if records[0].Name != "add_header" || !records[0].CalledNext {
+		t.Fatalf("unexpected first record: %+v", records[0])
+	}
+	if len(records[0].MetadataAdded) != 1 || records[0].MetadataAdded[0] != "x-added" {
+		t.Fatalf("expected x-added to be recorded, got %+v", records[0])
+	}
+	if len(records[0].MetadataRemoved) != 1 || records[0].MetadataRemoved[0] != "x-removed" {
+		t.Fatalf("expected x-removed to be recorded, got %+v", records[0])
+	}
+	if records[1].Name != "pass_through" || len(records[1].MetadataAdded) != 0 {
+		t.Fatalf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestNewInterceptorTraceRecordsShortCircuit(t *testing.T) {
+	reject := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+
+	var records []InterceptorTraceRecord
+	inspect := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		records = InterceptorTraceFromContext(ctx)
+		return resp, err
+	}
+
+	chain := NewInterceptorTrace(InterceptorTraceOptions{Enabled: true},
+		NamedUnaryInterceptor{Name: "inspect", Interceptor: inspect},
+		NamedUnaryInterceptor{Name: "reject", Interceptor: reject},
+	)
+	_, err := runUnaryChain(context.Background(), chain, &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req any) (any, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if len(records) != 2 || records[1].Name != "reject" || records[1].CalledNext || records[1].Error == "" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+}
+
+func TestNewInterceptorTraceDisabledReturnsOriginalInterceptors(t *testing.T) {
+	chain := NewInterceptorTrace(InterceptorTraceOptions{},
+		NamedUnaryInterceptor{Name: "a", Interceptor: ValidationErrorToInvalidArgument()},
+		NamedUnaryInterceptor{Name: "nil"},
+	)
+	if len(chain) != 1 {
+		t.Fatalf("expected only non-nil original interceptor, got %d", len(chain))
+	}
+}
@@ -0,0 +1,146 @@
+package gm
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// IPFilterRule 定义一组方法上的 IP 允许/拒绝规则。
+type IPFilterRule struct {
+	// Methods 表示规则作用的方法：完整方法名精确匹配，以 "/" 结尾的服务前缀按前缀匹配；
+	// 为空时作用于全部方法。
+	Methods []string
+	// Allow 表示允许的 IP 或 CIDR；非空时只有命中的来源可以访问。
+	Allow []string
+	// Deny 表示拒绝的 IP 或 CIDR，优先于 Allow。
+	Deny []string
+}
+
+// IPFilterOptions 定义 IP 过滤拦截器配置。
+type IPFilterOptions struct {
+	// Rules 按顺序匹配，只应用第一个命中方法的规则；没有命中规则的方法不做限制。
+	Rules []IPFilterRule
+	// TrustedProxies 表示可信代理的 IP 或 CIDR。只有对端地址属于可信代理时，
+	// 才采用 x-real-ip 作为来源 IP，否则一律使用对端地址，避免伪造 header 绕过规则。
+	TrustedProxies []string
+}
+
+// ipFilterRule 是预解析后的规则。
+type ipFilterRule struct {
+	methods methodMatcher
+	allow   []netip.Prefix
+	deny    []netip.Prefix
+}
+
+// matches 判断规则是否作用于指定方法；未配置方法时作用于全部方法。
+func (r *ipFilterRule) matches(method string) bool {
+	return r.methods.empty() || r.methods.matches(method)
+}
+
+// permits 判断来源 IP 是否被规则允许。
+func (r *ipFilterRule) permits(addr netip.Addr) bool {
+	if containsAddr(r.deny, addr) {
+		return false
+	}
+	return len(r.allow) == 0 || containsAddr(r.allow, addr)
+}
+
+// ipFilter 持有预解析后的全部规则。
+type ipFilter struct {
+	rules   []ipFilterRule
+	trusted []netip.Prefix
+}
+
+func newIPFilter(options IPFilterOptions) (*ipFilter, error) {
+	trusted, err := parsePrefixes(options.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	filter := &ipFilter{trusted: trusted, rules: make([]ipFilterRule, 0, len(options.Rules))}
+	for _, rule := range options.Rules {
+		parsed := ipFilterRule{methods: newMethodMatcher(rule.Methods)}
+		if parsed.allow, err = parsePrefixes(rule.Allow); err != nil {
+			return nil, err
+		}
+		if parsed.deny, err = parsePrefixes(rule.Deny); err != nil {
+			return nil, err
+		}
+		filter.rules = append(filter.rules, parsed)
+	}
+	return filter, nil
+}
+
+// check 校验当前请求来源；无法确定来源 IP 时按拒绝处理。
+func (f *ipFilter) check(ctx context.Context, method string) error {
+	for index := range f.rules {
+		rule := &f.rules[index]
+		if !rule.matches(method) {
+			continue
+		}
+		addr, ok := f.sourceAddr(ctx)
+		if !ok || !rule.permits(addr) {
+			return status.Error(codes.PermissionDenied, "source ip is not allowed")
+		}
+		return nil
+	}
+	return nil
+}
+
+// sourceAddr 解析来源 IP：对端属于可信代理且携带 x-real-ip 时使用 header，否则使用对端地址。
+func (f *ipFilter) sourceAddr(ctx context.Context) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+	peerAddr, ok := parseAddr(p.Addr.String())
+	if !ok {
+		return netip.Addr{}, false
+	}
+	if !containsAddr(f.trusted, peerAddr) {
+		return peerAddr, true
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if header := parseLogMetaKey(md, constant.XRealIp); header != "" {
+		if addr, ok := parseAddr(header); ok {
+			return addr, true
+		}
+	}
+	return peerAddr, true
+}
+
+// NewIPFilterUnaryInterceptor 创建基于 CIDR 的 IP 允许/拒绝 unary 拦截器，常用于锁定仅限内网访问的管理接口。
+//
+// 来源不被允许时返回 PermissionDenied；规则中的地址非法时返回 ErrIPFilterInvalidAddress。
+func NewIPFilterUnaryInterceptor(options IPFilterOptions) (grpc.UnaryServerInterceptor, error) {
+	filter, err := newIPFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := filter.check(ctx, unaryFullMethod(info)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}, nil
+}
+
+// NewIPFilterStreamInterceptor 是 NewIPFilterUnaryInterceptor 的 stream 版本。
+func NewIPFilterStreamInterceptor(options IPFilterOptions) (grpc.StreamServerInterceptor, error) {
+	filter, err := newIPFilter(options)
+	if err != nil {
+		return nil, err
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := filter.check(ss.Context(), streamFullMethod(info)); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}, nil
+}
@@ -0,0 +1,79 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewIPFilterUnaryInterceptor(t *testing.T) {
+	interceptor, err := NewIPFilterUnaryInterceptor(IPFilterOptions{
+		Rules: []IPFilterRule{
+			{Methods: []string{"/acme.admin.v1.AdminService/"}, Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.66"}},
+			{Deny: []string{"192.0.2.0/24"}},
+		},
+		TrustedProxies: []string{"172.16.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("new ip filter failed: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		peer   string
+		header string
+		want   codes.Code
+	}{
+		{name: "admin allowed", method: "/acme.admin.v1.AdminService/Reset", peer: "10.1.2.3:5000", want: codes.OK},
+		{name: "admin outside allow", method: "/acme.admin.v1.AdminService/Reset", peer: "203.0.113.5:5000", want: codes.PermissionDenied},
+		{name: "admin denied host", method: "/acme.admin.v1.AdminService/Reset", peer: "10.0.0.66:5000", want: codes.PermissionDenied},
+		{name: "trusted proxy header", method: "/acme.admin.v1.AdminService/Reset", peer: "172.16.0.1:5000", header: "10.9.9.9", want: codes.OK},
+		{name: "untrusted proxy header ignored", method: "/acme.admin.v1.AdminService/Reset", peer: "203.0.113.5:5000", header: "10.9.9.9", want: codes.PermissionDenied},
+		{name: "fallback rule deny", method: "/acme.order.v1.OrderService/Get", peer: "192.0.2.10:5000", want: codes.PermissionDenied},
+		{name: "fallback rule allow", method: "/acme.order.v1.OrderService/Get", peer: "198.51.100.1:5000", want: codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			host, port, _ := net.SplitHostPort(tc.peer)
+			addr := &net.TCPAddr{IP: net.ParseIP(host)}
+			addr.Port, _ = net.LookupPort("tcp", port)
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tc.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constant.XRealIp, tc.header))
+			}
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method},
+				func(ctx context.Context, req any) (any, error) { return "ok", nil })
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestNewIPFilterUnaryInterceptorRejectsMissingPeer(t *testing.T) {
+	interceptor, err := NewIPFilterUnaryInterceptor(IPFilterOptions{Rules: []IPFilterRule{{Allow: []string{"10.0.0.0/8"}}}})
+	if err != nil {
+		t.Fatalf("new ip filter failed: %v", err)
+	}
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) { return "ok", nil })
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied without peer, got %v", err)
+	}
+}
+
+func TestNewIPFilterUnaryInterceptorInvalidAddress(t *testing.T) {
+	_, err := NewIPFilterUnaryInterceptor(IPFilterOptions{Rules: []IPFilterRule{{Allow: []string{"10.0.0.0/33"}}}})
+	if !errors.Is(err, ErrIPFilterInvalidAddress) {
+		t.Fatalf("expected invalid address error, got %v", err)
+	}
+}
@@ -3,26 +3,123 @@ package gm
 import (
 	"context"
 	"encoding/json"
+	"math/rand/v2"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/fireflycore/go-micro/constant"
 	"github.com/fireflycore/go-micro/logger"
-	"github.com/fireflycore/go-micro/service"
+	"github.com/fireflycore/go-micro/rpcerror"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-const grpcHealthCheckFullMethod = "/grpc.health.v1.Health/Check"
+// errAccessLogPanic 是 handler panic 时访问日志记录的错误，与 recovery 默认返回的 codes.Internal 一致。
+var errAccessLogPanic = status.Error(codes.Internal, "panic recovered")
 
 // AccessLoggerOptions 定义 gRPC 访问日志中间件的可选配置。
 type AccessLoggerOptions struct {
 	// SkipMethods 表示需要跳过访问日志记录的完整 gRPC 方法名列表。
 	// 例如：/grpc.health.v1.Health/Check
 	SkipMethods []string
+	// MaxPayloadBytes 表示 request/response 报文写入日志的最大字节数，超出部分截断；
+	// <= 0 表示不限制。截断时附带 request_truncated/response_truncated 与原始大小字段。
+	MaxPayloadBytes int
+	// SlowThreshold 表示慢请求阈值，耗时不低于该值的请求标记 slow=true 且不参与采样；<= 0 表示不区分慢请求。
+	SlowThreshold time.Duration
+	// SlowLogger 非空时，慢请求额外输出一条 warn 日志，附带对端地址与阈值等排障信息。
+	SlowLogger *logger.ServerLogger
+	// Sampling 非空时对成功且非慢请求按比例采样，失败请求与慢请求始终记录。
+	Sampling *AccessLogSampling
+}
+
+// AccessLogSampling 定义访问日志采样规则。
+type AccessLogSampling struct {
+	// Percent 表示默认采样比例，取值 0~100；超过 100 按 100 处理。
+	Percent float64
+	// Methods 按完整 gRPC 方法名覆盖采样比例，优先于 Percent。
+	Methods map[string]float64
+}
+
+// percent 返回指定方法生效的采样比例。
+func (s *AccessLogSampling) percent(fullMethod string) float64 {
+	if v, ok := s.Methods[fullMethod]; ok {
+		return v
+	}
+	return s.Percent
+}
+
+// accessLoggerConfig 是合并多个 AccessLoggerOptions 后的最终配置。
+type accessLoggerConfig struct {
+	skipMethods     map[string]struct{}
+	maxPayloadBytes int
+	slowThreshold   time.Duration
+	slowLogger      *logger.ServerLogger
+	sampling        *AccessLogSampling
+}
+
+// buildAccessLoggerConfig 合并 options；标量配置以最后一个非零值为准。
+func buildAccessLoggerConfig(options ...AccessLoggerOptions) accessLoggerConfig {
+	config := accessLoggerConfig{skipMethods: buildAccessLogSkipMethods(options...)}
+	for _, option := range options {
+		if option.MaxPayloadBytes > 0 {
+			config.maxPayloadBytes = option.MaxPayloadBytes
+		}
+		if option.SlowThreshold > 0 {
+			config.slowThreshold = option.SlowThreshold
+		}
+		if option.SlowLogger != nil {
+			config.slowLogger = option.SlowLogger
+		}
+		if option.Sampling != nil {
+			config.sampling = option.Sampling
+		}
+	}
+	return config
+}
+
+// isSlow 判断本次请求是否达到慢请求阈值。
+func (c accessLoggerConfig) isSlow(elapsed time.Duration) bool {
+	return c.slowThreshold > 0 && elapsed >= c.slowThreshold
+}
+
+// flagSlow 在慢请求时追加 slow 标记，并按配置输出独立的慢请求 warn 日志。
+func (c accessLoggerConfig) flagSlow(ctx context.Context, fullMethod string, elapsed time.Duration, fields []zap.Field) []zap.Field {
+	if !c.isSlow(elapsed) {
+		return fields
+	}
+	if c.slowLogger != nil {
+		detail := []zap.Field{
+			zap.String("path", fullMethod),
+			zap.Uint64("duration", uint64(elapsed.Microseconds())),
+			zap.Uint64("slow_threshold", uint64(c.slowThreshold.Microseconds())),
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			detail = append(detail, zap.String("peer_addr", p.Addr.String()))
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			detail = append(detail, zap.Int64("deadline_remaining", time.Until(deadline).Microseconds()))
+		}
+		c.slowLogger.WithContextWarn(ctx, constant.GrpcSlowRequestLog, detail...)
+	}
+	return append(fields, zap.Bool("slow", true))
+}
+
+// sampled 判断本次请求是否应写出访问日志：失败与慢请求始终记录，其余按采样比例决定。
+func (c accessLoggerConfig) sampled(fullMethod string, elapsed time.Duration, err error) bool {
+	if c.sampling == nil || err != nil || c.isSlow(elapsed) {
+		return true
+	}
+	percent := c.sampling.percent(fullMethod)
+	if percent >= 100 {
+		return true
+	}
+	return percent > 0 && rand.Float64()*100 < percent
 }
 
 // NewAccessLogger 访问日志中间件
@@ -31,8 +128,8 @@ type AccessLoggerOptions struct {
 // - 默认跳过 gRPC health check，避免探针请求刷屏访问日志。
 // - 业务方也可以通过 options 追加自定义的跳过方法列表。
 func NewAccessLogger(log *logger.AccessLogger, options ...AccessLoggerOptions) grpc.UnaryServerInterceptor {
-	// 预先整理跳过规则，避免每次请求都重复构造。
-	skipMethods := buildAccessLogSkipMethods(options...)
+	// 预先整理跳过规则与报文限制，避免每次请求都重复构造。
+	config := buildAccessLoggerConfig(options...)
 
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// 没有 logger 时直接透传请求。
@@ -40,168 +137,288 @@ func NewAccessLogger(log *logger.AccessLogger, options ...AccessLoggerOptions) g
 			return handler(ctx, req)
 		}
 		// 命中跳过规则时，直接放行，不记录访问日志。
-		if shouldSkipAccessLog(info, skipMethods) {
+		if shouldSkipAccessLog(unaryFullMethod(info), config.skipMethods) {
 			return handler(ctx, req)
 		}
 
 		// 进入日志中间件时记录开始时间，用于后面计算耗时。
 		start := time.Now()
-		// 提前提取 metadata，后续用于补充访问日志字段。
-		md, _ := metadata.FromIncomingContext(ctx)
-		// 读取服务内部统一的 service.Context，优先复用已结构化的上下文数据。
-		serviceContext, _ := service.FromContext(ctx)
+		// 安装请求级注解容器，业务 handler 通过 logger.Annotate 追加的字段会自动写入本条访问日志。
+		ctx = logger.WithAnnotations(ctx)
+		// 访问日志位于 service_context 之前时，通过回传容器拿到内层构建的 service.Context。
+		ctx = withServiceContextHolder(ctx)
+
+		// handler panic 时不会正常返回，这里在 panic 继续向外传播前补写一条访问日志，由外层 recovery 负责恢复。
+		completed := false
+		defer func() {
+			if !completed {
+				writeAccessLog(ctx, log, info.FullMethod, time.Since(start), errAccessLogPanic, zap.Bool("panic", true))
+			}
+		}()
 
 		// 调用下一个拦截器或服务方法
 		resp, err := handler(ctx, req)
+		completed = true
 
-		// 计算请求实际耗时。
 		elapsed := time.Since(start)
-
-		// 默认按成功状态处理，若有错误再覆盖成对应 grpc code。
-		code := codes.OK
-		if err != nil {
-			code = status.Code(err)
+		// 未命中采样时跳过报文序列化与日志写出。
+		if !config.sampled(info.FullMethod, elapsed, err) {
+			return resp, err
 		}
 
-		// 预分配字段切片，减少 append 过程中的扩容。
-		fields := make([]zap.Field, 0, 32)
-		// 补齐访问日志的基础字段。
-		fields = append(fields,
-			zap.String("log_type", "access"),
-			zap.String("protocol", "grpc"),
-			zap.String("method", constant.RequestMethodGrpcString),
-			zap.String("path", info.FullMethod),
-			zap.Uint64("duration", uint64(elapsed.Microseconds())),
-			zap.Uint32("status", uint32(code)),
-		)
-
+		// unary 额外记录请求与响应报文。
+		payload := make([]zap.Field, 0, 7)
 		// 请求体可序列化时，记录请求报文。
 		if request, e := json.Marshal(req); e == nil {
-			fields = append(fields, zap.ByteString("request", request))
+			payload = appendPayloadField(payload, "request", request, config.maxPayloadBytes)
 		}
 		// 响应体可序列化时，记录响应报文。
 		if response, e := json.Marshal(resp); e == nil {
-			fields = append(fields, zap.ByteString("response", response))
+			payload = appendPayloadField(payload, "response", response, config.maxPayloadBytes)
+		}
+		payload = config.flagSlow(ctx, info.FullMethod, elapsed, payload)
+		writeAccessLog(ctx, log, info.FullMethod, elapsed, err, payload...)
+
+		// 返回下游处理结果。
+		return resp, err
+	}
+}
+
+// NewStreamAccessLogger 是 NewAccessLogger 的 stream 版本，跳过规则与日志字段保持一致。
+//
+// stream 不记录报文内容，改为记录收发消息数量。
+func NewStreamAccessLogger(log *logger.AccessLogger, options ...AccessLoggerOptions) grpc.StreamServerInterceptor {
+	config := buildAccessLoggerConfig(options...)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if log == nil || shouldSkipAccessLog(streamFullMethod(info), config.skipMethods) {
+			return handler(srv, ss)
 		}
 
-		// 从入站 metadata 中提取客户端 IP。
-		if v := parseLogMetaKey(md, constant.XRealIp); v != "" {
-			fields = append(fields, zap.String("client_ip", v))
+		start := time.Now()
+		stream := &accessLogServerStream{ServerStream: ss, ctx: withServiceContextHolder(logger.WithAnnotations(ss.Context()))}
+		completed := false
+		defer func() {
+			if !completed {
+				writeAccessLog(stream.ctx, log, info.FullMethod, time.Since(start), errAccessLogPanic,
+					zap.Bool("panic", true),
+					zap.Int64("recv_messages", stream.recvCount.Load()),
+					zap.Int64("sent_messages", stream.sentCount.Load()),
+				)
+			}
+		}()
+		err := handler(srv, stream)
+		completed = true
+
+		elapsed := time.Since(start)
+		if !config.sampled(info.FullMethod, elapsed, err) {
+			return err
 		}
+		fields := []zap.Field{
+			zap.Int64("recv_messages", stream.recvCount.Load()),
+			zap.Int64("sent_messages", stream.sentCount.Load()),
+		}
+		fields = config.flagSlow(stream.ctx, info.FullMethod, elapsed, fields)
+		writeAccessLog(stream.ctx, log, info.FullMethod, elapsed, err, fields...)
+		return err
+	}
+}
+
+// accessLogServerStream 包装 grpc.ServerStream，安装注解容器并统计收发消息数。
+type accessLogServerStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	sentCount atomic.Int64
+	recvCount atomic.Int64
+}
+
+// Context 返回安装了注解容器的 ctx。
+func (s *accessLogServerStream) Context() context.Context {
+	return s.ctx
+}
 
-		if v := parseLogMetaKey(md, constant.SystemName); v != "" {
-			fields = append(fields, zap.String("system_name", v))
+// SendMsg 发送消息并计数。
+func (s *accessLogServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sentCount.Add(1)
+	}
+	return err
+}
+
+// RecvMsg 接收消息并计数。
+func (s *accessLogServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recvCount.Add(1)
+	}
+	return err
+}
+
+// writeAccessLog 组装并写出一条访问日志，unary 与 stream 共用；extra 追加在基础字段之后。
+func writeAccessLog(ctx context.Context, log *logger.AccessLogger, fullMethod string, elapsed time.Duration, err error, extra ...zap.Field) {
+	// 提前提取 metadata，后续用于补充访问日志字段。
+	md, _ := metadata.FromIncomingContext(ctx)
+	// 读取服务内部统一的 service.Context，优先复用已结构化的上下文数据；访问日志在外层时从回传容器读取。
+	serviceContext := serviceContextForLog(ctx)
+
+	// 默认按成功状态处理，若有错误再覆盖成对应 grpc code。
+	code := codes.OK
+	if err != nil {
+		code = status.Code(err)
+	}
+
+	// 预分配字段切片，减少 append 过程中的扩容。
+	fields := make([]zap.Field, 0, 32)
+	// 补齐访问日志的基础字段。
+	fields = append(fields,
+		zap.String("log_type", "access"),
+		zap.String("protocol", "grpc"),
+		zap.String("method", constant.RequestMethodGrpcString),
+		zap.String("path", fullMethod),
+		zap.Uint64("duration", uint64(elapsed.Microseconds())),
+		zap.Uint32("status", uint32(code)),
+	)
+	fields = append(fields, extra...)
+
+	// 从入站 metadata 中提取客户端 IP。
+	if v := parseLogMetaKey(md, constant.XRealIp); v != "" {
+		fields = append(fields, zap.String("client_ip", v))
+	}
+
+	if v := parseLogMetaKey(md, constant.SystemName); v != "" {
+		fields = append(fields, zap.String("system_name", v))
+	}
+	if v := parseLogMetaKey(md, constant.ClientName); v != "" {
+		fields = append(fields, zap.String("client_name", v))
+	}
+	if raw := parseLogMetaKey(md, constant.SystemType); raw != "" {
+		fields = append(fields, zap.Uint32("system_type", parseInt32OrZero(raw)))
+	}
+	if raw := parseLogMetaKey(md, constant.ClientType); raw != "" {
+		fields = append(fields, zap.Uint32("client_type", parseInt32OrZero(raw)))
+	}
+	if v := parseLogMetaKey(md, constant.SystemVersion); v != "" {
+		fields = append(fields, zap.String("system_version", v))
+	}
+	if v := parseLogMetaKey(md, constant.ClientVersion); v != "" {
+		fields = append(fields, zap.String("client_version", v))
+	}
+	if v := parseLogMetaKey(md, constant.AppVersion); v != "" {
+		fields = append(fields, zap.String("app_version", v))
+	}
+	// 若入口已构建 service.Context，则优先使用结构化后的字段。
+	if serviceContext != nil {
+		// 记录当前业务服务自身 app_id，只表达本地服务身份，不参与 authz 权限主体判断。
+		if serviceContext.ServiceAppId != "" {
+			fields = append(fields, zap.String("service_app_id", serviceContext.ServiceAppId))
 		}
-		if v := parseLogMetaKey(md, constant.ClientName); v != "" {
-			fields = append(fields, zap.String("client_name", v))
+		// 记录当前业务服务自身实例 ID，用于实例级日志和 OTel 排障。
+		if serviceContext.ServiceInstanceId != "" {
+			fields = append(fields, zap.String("service_instance_id", serviceContext.ServiceInstanceId))
 		}
-		if raw := parseLogMetaKey(md, constant.SystemType); raw != "" {
-			fields = append(fields, zap.Uint32("system_type", parseInt32OrZero(raw)))
+		// 记录用户主体 ID，服务和匿名主体通常为空。
+		if serviceContext.UserId != "" {
+			fields = append(fields, zap.String("user_id", serviceContext.UserId))
 		}
-		if raw := parseLogMetaKey(md, constant.ClientType); raw != "" {
-			fields = append(fields, zap.Uint32("client_type", parseInt32OrZero(raw)))
+		// 记录用户身份中的 app_id；服务间调用方 app_id 使用 invoke_app_id 表达。
+		if serviceContext.AppId != "" {
+			fields = append(fields, zap.String("app_id", serviceContext.AppId))
 		}
-		if v := parseLogMetaKey(md, constant.SystemVersion); v != "" {
-			fields = append(fields, zap.String("system_version", v))
+		// 记录租户 ID，便于按租户聚合访问日志。
+		if serviceContext.TenantId != "" {
+			fields = append(fields, zap.String("tenant_id", serviceContext.TenantId))
 		}
-		if v := parseLogMetaKey(md, constant.ClientVersion); v != "" {
-			fields = append(fields, zap.String("client_version", v))
+		// 记录主体类型，区分 anonymous/user/service 三种入口。
+		if serviceContext.SubjectType != "" {
+			fields = append(fields, zap.String("subject_type", serviceContext.SubjectType))
 		}
-		if v := parseLogMetaKey(md, constant.AppVersion); v != "" {
-			fields = append(fields, zap.String("app_version", v))
+		// 记录调用方应用 ID，权限判断和访问日志统一使用该字段表达 caller。
+		if serviceContext.InvokeAppId != "" {
+			fields = append(fields, zap.String("invoke_app_id", serviceContext.InvokeAppId))
 		}
-		// 若入口已构建 service.Context，则优先使用结构化后的字段。
-		if serviceContext != nil {
-			// 记录当前业务服务自身 app_id，只表达本地服务身份，不参与 authz 权限主体判断。
-			if serviceContext.ServiceAppId != "" {
-				fields = append(fields, zap.String("service_app_id", serviceContext.ServiceAppId))
-			}
-			// 记录当前业务服务自身实例 ID，用于实例级日志和 OTel 排障。
-			if serviceContext.ServiceInstanceId != "" {
-				fields = append(fields, zap.String("service_instance_id", serviceContext.ServiceInstanceId))
-			}
-			// 记录用户主体 ID，服务和匿名主体通常为空。
-			if serviceContext.UserId != "" {
-				fields = append(fields, zap.String("user_id", serviceContext.UserId))
-			}
-			// 记录用户身份中的 app_id；服务间调用方 app_id 使用 invoke_app_id 表达。
-			if serviceContext.AppId != "" {
-				fields = append(fields, zap.String("app_id", serviceContext.AppId))
-			}
-			// 记录租户 ID，便于按租户聚合访问日志。
-			if serviceContext.TenantId != "" {
-				fields = append(fields, zap.String("tenant_id", serviceContext.TenantId))
-			}
-			// 记录主体类型，区分 anonymous/user/service 三种入口。
-			if serviceContext.SubjectType != "" {
-				fields = append(fields, zap.String("subject_type", serviceContext.SubjectType))
-			}
-			// 记录调用方应用 ID，权限判断和访问日志统一使用该字段表达 caller。
-			if serviceContext.InvokeAppId != "" {
-				fields = append(fields, zap.String("invoke_app_id", serviceContext.InvokeAppId))
-			}
-			// 记录被访问资源所属 app_id，便于排查跨应用调用。
-			if serviceContext.TargetAppId != "" {
-				fields = append(fields, zap.String("target_app_id", serviceContext.TargetAppId))
-			}
-			// 授权动作和路径已由 access log 基础字段 method/path 表达，避免重复写入同名字段。
-			// 记录 authz 决策 ID，用于把业务日志和授权判定关联起来。
-			if serviceContext.DecisionId != "" {
-				fields = append(fields, zap.String("decision_id", serviceContext.DecisionId))
-			}
-		} else {
-			// 没有 service.Context 时，再回退到原始 metadata 中兜底提取。
-			// 兜底记录当前业务服务自身 app_id。
-			if v := parseLogMetaKey(md, constant.ServiceAppId); v != "" {
-				fields = append(fields, zap.String("service_app_id", v))
-			}
-			// 兜底记录当前业务服务自身实例 ID。
-			if v := parseLogMetaKey(md, constant.ServiceInstanceId); v != "" {
-				fields = append(fields, zap.String("service_instance_id", v))
-			}
-			// 兜底记录用户主体 ID。
-			if v := parseLogMetaKey(md, constant.UserId); v != "" {
-				fields = append(fields, zap.String("user_id", v))
-			}
-			// 兜底记录用户身份中的 app_id。
-			if v := parseLogMetaKey(md, constant.AppId); v != "" {
-				fields = append(fields, zap.String("app_id", v))
-			}
-			// 兜底记录租户 ID。
-			if v := parseLogMetaKey(md, constant.TenantId); v != "" {
-				fields = append(fields, zap.String("tenant_id", v))
-			}
-			// 兜底记录主体类型。
-			if v := parseLogMetaKey(md, constant.SubjectType); v != "" {
-				fields = append(fields, zap.String("subject_type", v))
-			}
-			// 兜底记录调用方 app_id。
-			if v := parseLogMetaKey(md, constant.InvokeAppId); v != "" {
-				fields = append(fields, zap.String("invoke_app_id", v))
-			}
-			// 兜底记录被访问资源所属 app_id。
-			if v := parseLogMetaKey(md, constant.TargetAppId); v != "" {
-				fields = append(fields, zap.String("target_app_id", v))
-			}
-			// 不从普通 metadata 兜底读取授权动作和路径，避免信任未签名资源字段。
-			// 兜底记录 authz 决策 ID。
-			if v := parseLogMetaKey(md, constant.DecisionId); v != "" {
-				fields = append(fields, zap.String("decision_id", v))
-			}
+		// 记录被访问资源所属 app_id，便于排查跨应用调用。
+		if serviceContext.TargetAppId != "" {
+			fields = append(fields, zap.String("target_app_id", serviceContext.TargetAppId))
+		}
+		// 授权动作和路径已由 access log 基础字段 method/path 表达，避免重复写入同名字段。
+		// 记录 authz 决策 ID，用于把业务日志和授权判定关联起来。
+		if serviceContext.DecisionId != "" {
+			fields = append(fields, zap.String("decision_id", serviceContext.DecisionId))
+		}
+	} else {
+		// 没有 service.Context 时，再回退到原始 metadata 中兜底提取。
+		// 兜底记录当前业务服务自身 app_id。
+		if v := parseLogMetaKey(md, constant.ServiceAppId); v != "" {
+			fields = append(fields, zap.String("service_app_id", v))
+		}
+		// 兜底记录当前业务服务自身实例 ID。
+		if v := parseLogMetaKey(md, constant.ServiceInstanceId); v != "" {
+			fields = append(fields, zap.String("service_instance_id", v))
+		}
+		// 兜底记录用户主体 ID。
+		if v := parseLogMetaKey(md, constant.UserId); v != "" {
+			fields = append(fields, zap.String("user_id", v))
+		}
+		// 兜底记录用户身份中的 app_id。
+		if v := parseLogMetaKey(md, constant.AppId); v != "" {
+			fields = append(fields, zap.String("app_id", v))
 		}
+		// 兜底记录租户 ID。
+		if v := parseLogMetaKey(md, constant.TenantId); v != "" {
+			fields = append(fields, zap.String("tenant_id", v))
+		}
+		// 兜底记录主体类型。
+		if v := parseLogMetaKey(md, constant.SubjectType); v != "" {
+			fields = append(fields, zap.String("subject_type", v))
+		}
+		// 兜底记录调用方 app_id。
+		if v := parseLogMetaKey(md, constant.InvokeAppId); v != "" {
+			fields = append(fields, zap.String("invoke_app_id", v))
+		}
+		// 兜底记录被访问资源所属 app_id。
+		if v := parseLogMetaKey(md, constant.TargetAppId); v != "" {
+			fields = append(fields, zap.String("target_app_id", v))
+		}
+		// 不从普通 metadata 兜底读取授权动作和路径，避免信任未签名资源字段。
+		// 兜底记录 authz 决策 ID。
+		if v := parseLogMetaKey(md, constant.DecisionId); v != "" {
+			fields = append(fields, zap.String("decision_id", v))
+		}
+	}
+
+	// 开启拦截器执行追踪时，附带当前已完成的拦截器执行摘要。
+	if records := InterceptorTraceFromContext(ctx); len(records) > 0 {
+		fields = append(fields, zap.Any("interceptor_trace", records))
+	}
 
-		// 有错误时按 error 级别记录，并附带 error 字段。
-		if err != nil {
-			fields = append(fields, zap.Error(err))
-			log.WithContextError(ctx, constant.GrpcAccessLog, fields...)
-		} else {
-			// 成功请求按 info 级别记录。
-			log.WithContextInfo(ctx, constant.GrpcAccessLog, fields...)
+	// 有错误时按 error 级别记录，并附带错误类别、来源与 error 字段。
+	if err != nil {
+		fields = append(fields,
+			zap.String("error_class", rpcerror.Classify(code)),
+			zap.String("error_origin", rpcerror.Origin(err)),
+		)
+		if upstream, ok := rpcerror.UpstreamFromError(err); ok && upstream != "" {
+			fields = append(fields, zap.String("error_upstream", upstream))
 		}
+		fields = append(fields, zap.Error(err))
+		log.WithContextError(ctx, constant.GrpcAccessLog, fields...)
+	} else {
+		// 成功请求按 info 级别记录。
+		log.WithContextInfo(ctx, constant.GrpcAccessLog, fields...)
+	}
+}
 
-		// 返回下游处理结果。
-		return resp, err
+// appendPayloadField 追加报文字段，超过 maxBytes 时截断并标记原始大小。
+func appendPayloadField(fields []zap.Field, key string, data []byte, maxBytes int) []zap.Field {
+	if maxBytes <= 0 || len(data) <= maxBytes {
+		return append(fields, zap.ByteString(key, data))
 	}
+	return append(fields,
+		zap.ByteString(key, data[:maxBytes]),
+		zap.Bool(key+"_truncated", true),
+		zap.Int(key+"_size", len(data)),
+	)
 }
 
 // buildAccessLogSkipMethods 构造最终的跳过方法集合。
@@ -209,6 +426,7 @@ func buildAccessLogSkipMethods(options ...AccessLoggerOptions) map[string]struct
 	// 默认跳过健康检查，避免探针请求占满访问日志。
 	methods := map[string]struct{}{
 		grpcHealthCheckFullMethod: {},
+		grpcHealthWatchFullMethod: {},
 	}
 
 	// 合并业务方传入的自定义跳过列表。
@@ -225,13 +443,13 @@ func buildAccessLogSkipMethods(options ...AccessLoggerOptions) map[string]struct
 }
 
 // shouldSkipAccessLog 判断当前请求是否应跳过访问日志。
-func shouldSkipAccessLog(info *grpc.UnaryServerInfo, skipMethods map[string]struct{}) bool {
+func shouldSkipAccessLog(fullMethod string, skipMethods map[string]struct{}) bool {
 	// 缺少方法信息时不跳过，保持默认记录行为。
-	if info == nil || info.FullMethod == "" {
+	if fullMethod == "" {
 		return false
 	}
 	// 命中跳过集合则直接返回 true。
-	_, ok := skipMethods[info.FullMethod]
+	_, ok := skipMethods[fullMethod]
 	return ok
 }
 
@@ -2,13 +2,19 @@ package gm
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/rpcerror"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewAccessLoggerSkipsHealthCheckByDefault(t *testing.T) {
@@ -90,3 +96,194 @@ func TestNewAccessLoggerLogsNonSkippedMethod(t *testing.T) {
 		t.Fatalf("expected one access log for non-skipped method, got %d", got)
 	}
 }
+
+func TestNewAccessLoggerRecordsErrorClassAndOrigin(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	accessLogger := logger.NewAccessLogger(zap.New(baseCore))
+	interceptor := NewAccessLogger(accessLogger)
+
+	upstreamErr := rpcerror.MarkUpstream(status.Error(codes.DeadlineExceeded, "auth timeout"), "auth")
+	_, _ = interceptor(
+		context.Background(),
+		map[string]string{"k": "v"},
+		&grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, upstreamErr
+		},
+	)
+
+	entries := observed.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one access log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["status"] != uint32(codes.DeadlineExceeded) {
+		t.Fatalf("unexpected status: %v", fields["status"])
+	}
+	if fields["error_class"] != rpcerror.ClassTimeout || fields["error_origin"] != rpcerror.OriginUpstream || fields["error_upstream"] != "auth" {
+		t.Fatalf("unexpected error fields: %v", fields)
+	}
+}
+
+func TestNewAccessLoggerIncludesHandlerAnnotations(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	accessLogger := logger.NewAccessLogger(zap.New(baseCore))
+	interceptor := NewAccessLogger(accessLogger)
+
+	_, _ = interceptor(
+		context.Background(),
+		map[string]string{"k": "v"},
+		&grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			logger.Annotate(ctx, "order_id", "o-1")
+			return map[string]string{"status": "ok"}, nil
+		},
+	)
+
+	if got := observed.All()[0].ContextMap()["order_id"]; got != "o-1" {
+		t.Fatalf("expected annotation in access log, got %v", got)
+	}
+}
+
+func TestNewStreamAccessLoggerCountsMessages(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewStreamAccessLogger(logger.NewAccessLogger(zap.New(baseCore)))
+
+	err := interceptor(nil, &countingServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"},
+		func(srv any, stream grpc.ServerStream) error {
+			logger.Annotate(stream.Context(), "order_id", "o-1")
+			_ = stream.RecvMsg(nil)
+			_ = stream.SendMsg(nil)
+			_ = stream.SendMsg(nil)
+			return status.Error(codes.Canceled, "client gone")
+		})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := observed.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one access log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["path"] != "/example.Service/Watch" || fields["status"] != uint32(codes.Canceled) {
+		t.Fatalf("unexpected base fields: %v", fields)
+	}
+	if fields["recv_messages"] != int64(1) || fields["sent_messages"] != int64(2) {
+		t.Fatalf("unexpected message counts: %v", fields)
+	}
+	if fields["order_id"] != "o-1" {
+		t.Fatalf("expected annotation in stream access log, got %v", fields["order_id"])
+	}
+}
+
+func TestNewStreamAccessLoggerSkipsHealthWatchByDefault(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewStreamAccessLogger(logger.NewAccessLogger(zap.New(baseCore)))
+
+	_ = interceptor(nil, &countingServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: grpcHealthWatchFullMethod},
+		func(srv any, stream grpc.ServerStream) error { return nil })
+	if got := observed.Len(); got != 0 {
+		t.Fatalf("expected no access logs for health watch, got %d", got)
+	}
+}
+
+// countingServerStream 是收发均直接成功的最小 ServerStream 实现。
+type countingServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *countingServerStream) Context() context.Context { return s.ctx }
+func (s *countingServerStream) SendMsg(m any) error      { return nil }
+func (s *countingServerStream) RecvMsg(m any) error      { return nil }
+
+func TestNewAccessLoggerTruncatesLargePayload(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewAccessLogger(logger.NewAccessLogger(zap.New(baseCore)), AccessLoggerOptions{MaxPayloadBytes: 8})
+
+	_, _ = interceptor(
+		context.Background(),
+		"k",
+		&grpc.UnaryServerInfo{FullMethod: "/example.Service/Upload"},
+		func(ctx context.Context, req any) (any, error) {
+			return "0123456789abcdef", nil
+		},
+	)
+
+	fields := observed.All()[0].ContextMap()
+	if fields["request"] != `"k"` || fields["request_truncated"] != nil {
+		t.Fatalf("expected small request untouched: %v", fields)
+	}
+	if fields["response"] != `"0123456` || fields["response_truncated"] != true || fields["response_size"] != int64(18) {
+		t.Fatalf("expected truncated response: %v", fields)
+	}
+}
+
+func TestNewAccessLoggerSampling(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewAccessLogger(logger.NewAccessLogger(zap.New(baseCore)), AccessLoggerOptions{
+		SlowThreshold: time.Millisecond,
+		Sampling: &AccessLogSampling{
+			Percent: 0,
+			Methods: map[string]float64{"/example.Service/Audit": 100},
+		},
+	})
+	call := func(method string, handler grpc.UnaryHandler) {
+		_, _ = interceptor(context.Background(), "k", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+	ok := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	call("/example.Service/Get", ok)
+	if got := observed.Len(); got != 0 {
+		t.Fatalf("expected fast success to be sampled out, got %d logs", got)
+	}
+	call("/example.Service/Get", func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+	call("/example.Service/Get", func(ctx context.Context, req any) (any, error) {
+		time.Sleep(2 * time.Millisecond)
+		return "ok", nil
+	})
+	call("/example.Service/Audit", ok)
+	if got := observed.Len(); got != 3 {
+		t.Fatalf("expected errors, slow requests and method override to be logged, got %d", got)
+	}
+}
+
+func TestNewAccessLoggerFlagsSlowRequest(t *testing.T) {
+	accessCore, accessObserved := observer.New(zapcore.InfoLevel)
+	slowCore, slowObserved := observer.New(zapcore.InfoLevel)
+	interceptor := NewAccessLogger(logger.NewAccessLogger(zap.New(accessCore)), AccessLoggerOptions{
+		SlowThreshold: time.Millisecond,
+		SlowLogger:    logger.NewServerLogger(zap.New(slowCore)),
+	})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	_, _ = interceptor(ctx, "k", &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			time.Sleep(2 * time.Millisecond)
+			return "ok", nil
+		})
+	_, _ = interceptor(ctx, "k", &grpc.UnaryServerInfo{FullMethod: "/example.Service/Fast"},
+		func(ctx context.Context, req any) (any, error) { return "ok", nil })
+
+	entries := accessObserved.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected two access logs, got %d", len(entries))
+	}
+	if entries[0].ContextMap()["slow"] != true {
+		t.Fatalf("expected slow flag on slow request: %v", entries[0].ContextMap())
+	}
+	if _, ok := entries[1].ContextMap()["slow"]; ok {
+		t.Fatalf("expected no slow flag on fast request: %v", entries[1].ContextMap())
+	}
+
+	slowEntries := slowObserved.All()
+	if len(slowEntries) != 1 || slowEntries[0].Level != zapcore.WarnLevel {
+		t.Fatalf("expected one slow warn log, got %v", slowEntries)
+	}
+	if got := slowEntries[0].ContextMap()["peer_addr"]; got != "10.0.0.1:5000" {
+		t.Fatalf("unexpected peer address: %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMaintenanceUnaryInterceptor(t *testing.T) {
+	maintenance := NewMaintenance(MaintenanceOptions{
+		Methods:    []string{"/example.OrderService/"},
+		RetryAfter: time.Minute,
+	})
+	interceptor := maintenance.UnaryInterceptor()
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			return "ok", nil
+		})
+		return err
+	}
+
+	if err := call("/example.OrderService/Create"); err != nil {
+		t.Fatalf("expected pass while disabled, got %v", err)
+	}
+
+	maintenance.Enable()
+	err := call("/example.OrderService/Create")
+	if status.Code(err) != codes.Unavailable || status.Convert(err).Message() != DefaultMaintenanceMessage {
+		t.Fatalf("expected Unavailable during maintenance, got %v", err)
+	}
+	if hint, ok := rpcerror.RetryHintFromError(err); !ok || hint.RetryAfter != time.Minute {
+		t.Fatalf("expected retry hint, got %+v", hint)
+	}
+	// 未覆盖的服务与健康检查不受影响。
+	if err := call("/example.UserService/Get"); err != nil {
+		t.Fatalf("expected uncovered service to pass, got %v", err)
+	}
+	if err := call(grpcHealthCheckFullMethod); err != nil {
+		t.Fatalf("expected health check to pass, got %v", err)
+	}
+
+	maintenance.Disable()
+	if err := call("/example.OrderService/Create"); err != nil {
+		t.Fatalf("expected pass after disable, got %v", err)
+	}
+}
+
+func TestMaintenanceStreamInterceptor(t *testing.T) {
+	maintenance := NewMaintenance(MaintenanceOptions{Message: "draining", Enabled: true})
+	interceptor := maintenance.StreamInterceptor()
+
+	err := interceptor(nil, &countingServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"},
+		func(srv any, stream grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.Unavailable || status.Convert(err).Message() != "draining" {
+		t.Fatalf("expected Unavailable for new stream, got %v", err)
+	}
+	if err := interceptor(nil, &countingServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: grpcHealthWatchFullMethod},
+		func(srv any, stream grpc.ServerStream) error { return nil }); err != nil {
+		t.Fatalf("expected health watch to pass, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package gm
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+const (
+	grpcHealthCheckFullMethod = "/grpc.health.v1.Health/Check"
+	grpcHealthWatchFullMethod = "/grpc.health.v1.Health/Watch"
+)
+
+// isHealthMethod 判断是否为 gRPC 健康检查方法。
+//
+// 限流、配额、超时、维护模式等拦截器始终放行健康检查，避免探针在过载或维护期间失败，
+// 导致实例被编排系统摘除或重启，进一步放大故障。
+func isHealthMethod(method string) bool {
+	return method == grpcHealthCheckFullMethod || method == grpcHealthWatchFullMethod
+}
+
+// methodMatcher 匹配完整方法名：精确方法名，或以 "/" 结尾的服务前缀（如 "/acme.admin.v1.AdminService/"）。
+type methodMatcher struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+// newMethodMatcher 把方法列表拆分为精确方法名与服务前缀。
+func newMethodMatcher(methods []string) methodMatcher {
+	var matcher methodMatcher
+	var exact []string
+	for _, method := range methods {
+		if strings.HasSuffix(method, "/") {
+			matcher.prefixes = append(matcher.prefixes, method)
+		} else {
+			exact = append(exact, method)
+		}
+	}
+	matcher.exact = buildMethodSet(exact)
+	return matcher
+}
+
+// empty 判断是否没有配置任何方法，调用方据此决定"为空时作用于全部方法"等默认语义。
+func (m methodMatcher) empty() bool {
+	return len(m.exact) == 0 && len(m.prefixes) == 0
+}
+
+// matches 判断方法是否命中精确方法名或服务前缀。
+func (m methodMatcher) matches(method string) bool {
+	if _, ok := m.exact[method]; ok {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefixes 把 IP 或 CIDR 列表解析为 netip.Prefix；单个 IP 视为 /32 或 /128。
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrIPFilterInvalidAddress, value)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrIPFilterInvalidAddress, value)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// parseAddr 解析可能带端口的地址，并把 IPv4-mapped IPv6 还原为 IPv4。
+func parseAddr(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// containsAddr 判断地址是否命中任一前缀。
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
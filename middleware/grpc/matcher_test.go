@@ -0,0 +1,32 @@
+package gm
+
+import "testing"
+
+func TestMethodMatcher(t *testing.T) {
+	matcher := newMethodMatcher([]string{"/acme.order.v1.OrderService/Get", "/acme.admin.v1.AdminService/", ""})
+	if matcher.empty() {
+		t.Fatalf("expected configured matcher not to be empty")
+	}
+	for method, want := range map[string]bool{
+		"/acme.order.v1.OrderService/Get":    true,
+		"/acme.order.v1.OrderService/List":   false,
+		"/acme.admin.v1.AdminService/Reload": true,
+		"":                                   false,
+	} {
+		if got := matcher.matches(method); got != want {
+			t.Fatalf("matches(%q) = %v, want %v", method, got, want)
+		}
+	}
+	if !newMethodMatcher(nil).empty() {
+		t.Fatalf("expected matcher without methods to be empty")
+	}
+}
+
+func TestIsHealthMethod(t *testing.T) {
+	if !isHealthMethod(grpcHealthCheckFullMethod) || !isHealthMethod(grpcHealthWatchFullMethod) {
+		t.Fatalf("expected grpc health methods to be recognized")
+	}
+	if isHealthMethod("/acme.order.v1.OrderService/Get") {
+		t.Fatalf("expected business method not to be a health method")
+	}
+}
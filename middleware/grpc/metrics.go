@@ -0,0 +1,136 @@
+package gm
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fireflycore/go-micro/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// metricAttrRPCMethod 表示 full method 指标标签，所有 rpc 指标共用以便按方法关联。
+	metricAttrRPCMethod = "rpc.method"
+	// metricAttrInvokeServiceAppId 表示调用方服务 app_id 指标标签。
+	metricAttrInvokeServiceAppId = "invoke_service_app_id"
+	// metricAttrTargetServiceAppId 表示被调用服务 app_id 指标标签。
+	metricAttrTargetServiceAppId = "target_service_app_id"
+)
+
+// MetricsOptions 定义 RPC 指标拦截器配置。
+type MetricsOptions struct {
+	// ServiceAppId 表示当前服务 app_id。
+	//
+	// 服务端拦截器在 service.Context 缺少 TargetServiceAppId 时用它兜底；
+	// 客户端拦截器用它作为 invoke_service_app_id。
+	ServiceAppId string
+}
+
+// rpcMetrics 汇总单侧（server / client）的 RPC 指标。
+type rpcMetrics struct {
+	// calls 记录完成的 RPC 次数，按 method、code、调用双方聚合。
+	calls metric.Int64Counter
+	// duration 记录 RPC 耗时分布。
+	duration metric.Float64Histogram
+	// active 记录正在处理的 RPC 数量。
+	active metric.Int64UpDownCounter
+}
+
+// newRPCMetrics 通过全局 MeterProvider 创建指标；side 取值为 server / client。
+func newRPCMetrics(side string) *rpcMetrics {
+	meter := otel.Meter(pkgName)
+	calls, _ := meter.Int64Counter("rpc."+side+".calls",
+		metric.WithDescription("Number of completed unary RPCs."),
+	)
+	duration, _ := meter.Float64Histogram("rpc."+side+".call.duration",
+		metric.WithDescription("Duration of unary RPCs."),
+		metric.WithUnit("ms"),
+	)
+	active, _ := meter.Int64UpDownCounter("rpc."+side+".active_calls",
+		metric.WithDescription("Number of in-flight unary RPCs."),
+	)
+	return &rpcMetrics{calls: calls, duration: duration, active: active}
+}
+
+// observe 包装一次 RPC 调用，记录在途数量、次数与耗时。
+func (m *rpcMetrics) observe(ctx context.Context, method string, invokeAppId string, targetAppId string, call func() error) error {
+	attrs := []attribute.KeyValue{
+		attribute.String(metricAttrRPCMethod, method),
+		attribute.String(metricAttrInvokeServiceAppId, invokeAppId),
+		attribute.String(metricAttrTargetServiceAppId, targetAppId),
+	}
+	activeAttrs := metric.WithAttributes(attrs...)
+	m.active.Add(ctx, 1, activeAttrs)
+	start := time.Now()
+
+	err := call()
+
+	elapsed := float64(time.Since(start).Microseconds()) / 1000
+	m.active.Add(ctx, -1, activeAttrs)
+	completed := metric.WithAttributes(append(attrs, attribute.String("rpc.grpc.status_code", status.Code(err).String()))...)
+	m.calls.Add(ctx, 1, completed)
+	m.duration.Record(ctx, elapsed, completed)
+	return err
+}
+
+// NewServerMetricsInterceptor 创建服务端 RPC 指标 unary 拦截器。
+//
+// 调用方与被调用方 app_id 读取自 service.Context，因此应放在 NewServiceContextUnaryInterceptor 之后。
+// 指标通过全局 MeterProvider 导出，配合 telemetry.NewMeterProvider 返回的 handler 暴露 /metrics。
+func NewServerMetricsInterceptor(options MetricsOptions) grpc.UnaryServerInterceptor {
+	metrics := newRPCMetrics("server")
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		invokeAppId := ""
+		targetAppId := options.ServiceAppId
+		if value, ok := service.FromContext(ctx); ok {
+			invokeAppId = value.InvokeServiceAppId
+			if value.TargetServiceAppId != "" {
+				targetAppId = value.TargetServiceAppId
+			}
+		}
+		err = metrics.observe(ctx, info.FullMethod, invokeAppId, targetAppId, func() error {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// NewClientMetricsInterceptor 创建客户端 RPC 指标 unary 拦截器。
+//
+// target_service_app_id 取自连接 target 的首段主机名，
+// 对 invocation 构造的 auth.default.svc.cluster.local 即为 auth。
+func NewClientMetricsInterceptor(options MetricsOptions) grpc.UnaryClientInterceptor {
+	metrics := newRPCMetrics("client")
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return metrics.observe(ctx, method, options.ServiceAppId, targetServiceName(cc), func() error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// targetServiceName 从 grpc target 中提取服务名，例如 dns:///auth.default.svc.cluster.local:9090 -> auth。
+func targetServiceName(cc *grpc.ClientConn) string {
+	if cc == nil {
+		return ""
+	}
+	target := cc.Target()
+	if index := strings.LastIndex(target, "/"); index >= 0 {
+		target = target[index+1:]
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		target = host
+	}
+	// IP 地址没有服务名语义，原样返回。
+	if net.ParseIP(target) != nil {
+		return target
+	}
+	name, _, _ := strings.Cut(target, ".")
+	return name
+}
@@ -0,0 +1,79 @@
+package gm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fireflycore/go-micro/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerMetricsInterceptorRecordsCallsByCodeAndApps(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(previous)
+
+	interceptor := NewServerMetricsInterceptor(MetricsOptions{ServiceAppId: "order"})
+	ctx := service.WithContext(context.Background(), &service.Context{InvokeServiceAppId: "gateway"})
+	_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("collect metrics failed: %v", err)
+	}
+	calls := findSum(t, data, "rpc.server.calls")
+	if len(calls.DataPoints) != 1 || calls.DataPoints[0].Value != 1 {
+		t.Fatalf("unexpected calls data points: %+v", calls.DataPoints)
+	}
+	attrs := calls.DataPoints[0].Attributes
+	for key, want := range map[attribute.Key]string{
+		"rpc.method":                 "/example.Service/Get",
+		"rpc.grpc.status_code":       "NotFound",
+		metricAttrInvokeServiceAppId: "gateway",
+		metricAttrTargetServiceAppId: "order",
+	} {
+		if got, _ := attrs.Value(key); got.AsString() != want {
+			t.Fatalf("unexpected %s: %q", key, got.AsString())
+		}
+	}
+	active := findSum(t, data, "rpc.server.active_calls")
+	if len(active.DataPoints) != 1 || active.DataPoints[0].Value != 0 {
+		t.Fatalf("expected no active calls, got %+v", active.DataPoints)
+	}
+}
+
+func TestTargetServiceName(t *testing.T) {
+	conn, err := grpc.NewClient("dns:///auth.default.svc.cluster.local:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if got := targetServiceName(conn); got != "auth" {
+		t.Fatalf("unexpected target service name: %s", got)
+	}
+}
+
+// findSum 按名称查找 int64 Sum 指标。
+func findSum(t *testing.T, data metricdata.ResourceMetrics, name string) metricdata.Sum[int64] {
+	t.Helper()
+	for _, scope := range data.ScopeMetrics {
+		for _, item := range scope.Metrics {
+			if item.Name == name {
+				return item.Data.(metricdata.Sum[int64])
+			}
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return metricdata.Sum[int64]{}
+}
@@ -0,0 +1,110 @@
+package gm
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMirrorTimeout 是镜像调用的默认超时时间。
+const DefaultMirrorTimeout = 5 * time.Second
+
+// MirrorOptions 定义流量镜像客户端拦截器配置。
+type MirrorOptions struct {
+	// Conn 表示影子版本服务的连接，例如 ConnectionManager.Dial 影子 Service 返回的连接；为空时不镜像。
+	Conn grpc.ClientConnInterface
+	// Percent 表示镜像比例，取值 0~100；非正数时不镜像。
+	Percent float64
+	// Methods 表示参与镜像的 full method；为空时所有方法都参与。
+	Methods []string
+	// Timeout 表示镜像调用超时时间；未设置时使用 DefaultMirrorTimeout。
+	Timeout time.Duration
+	// Logger 用于记录镜像调用失败；为空时静默丢弃错误。
+	Logger *logger.ServerLogger
+}
+
+// normalize 补齐默认值。
+func (o MirrorOptions) normalize() MirrorOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultMirrorTimeout
+	}
+	if o.Percent > 100 {
+		o.Percent = 100
+	}
+	return o
+}
+
+// NewMirrorInterceptor 创建流量镜像 unary 客户端拦截器，用生产流量验证影子版本。
+//
+// 按比例抽中的请求会先深拷贝，再异步发往 Conn，影子调用携带 x-firefly-shadow 标记，
+// 响应被丢弃、错误只记录日志，不影响主调用的结果与耗时。
+// 影子调用脱离主调用的取消信号，只受 Timeout 约束。
+func NewMirrorInterceptor(options MirrorOptions) grpc.UnaryClientInterceptor {
+	options = options.normalize()
+
+	var methods map[string]struct{}
+	if len(options.Methods) > 0 {
+		methods = make(map[string]struct{}, len(options.Methods))
+		for _, method := range options.Methods {
+			methods[method] = struct{}{}
+		}
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if options.Conn != nil && options.Percent > 0 && mirrorMethodAllowed(methods, method) && rand.Float64()*100 < options.Percent {
+			request, reqOk := req.(proto.Message)
+			response, replyOk := reply.(proto.Message)
+			if reqOk && replyOk {
+				// 影子调用在主调用返回后才可能序列化请求，此时调用方可能已修改或复用 req，必须先复制一份。
+				go mirrorCall(ctx, options, method, proto.Clone(request), response.ProtoReflect().New().Interface())
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// mirrorMethodAllowed 判断方法是否参与镜像；未配置方法列表时全部参与。
+func mirrorMethodAllowed(methods map[string]struct{}, method string) bool {
+	if methods == nil {
+		return true
+	}
+	_, ok := methods[method]
+	return ok
+}
+
+// mirrorCall 向影子服务发起一次调用并丢弃响应。
+func mirrorCall(ctx context.Context, options MirrorOptions, method string, req any, reply proto.Message) {
+	mirrorCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), options.Timeout)
+	defer cancel()
+	// 进程内可信标记保证影子连接上的出站清理与透传拦截器保留镜像标记。
+	mirrorCtx = service.WithShadow(mirrorCtx)
+
+	// 复制出站 metadata 后追加镜像标记，避免修改主调用的 metadata。
+	md, _ := metadata.FromOutgoingContext(mirrorCtx)
+	md = md.Copy()
+	md.Set(constant.Shadow, "1")
+	mirrorCtx = metadata.NewOutgoingContext(mirrorCtx, md)
+
+	if err := options.Conn.Invoke(mirrorCtx, method, req, reply); err != nil && options.Logger != nil {
+		options.Logger.WithContextWarn(ctx, constant.GrpcMirrorLog,
+			zap.String("method", method),
+			zap.Error(err),
+		)
+	}
+}
+
+// IsShadowRequest 判断当前入站请求是否为镜像流量，handler 据此跳过扣款、发消息等外部副作用。
+//
+// 入站 x-firefly-shadow 只有经 header 规范化拦截器确认来自可信对端后才生效，
+// 外部调用方直接携带该 header 不会被识别为镜像流量，也不会沿调用链透传。
+func IsShadowRequest(ctx context.Context) bool {
+	return service.IsShadow(ctx)
+}
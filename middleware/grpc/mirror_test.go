@@ -0,0 +1,132 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// shadowConn 记录镜像调用收到的 metadata。
+type shadowConn struct {
+	calls chan metadata.MD
+}
+
+func (c *shadowConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.calls <- md
+	return nil
+}
+
+func (c *shadowConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, nil
+}
+
+func TestNewMirrorInterceptorCopiesTrafficWithShadowFlag(t *testing.T) {
+	shadow := &shadowConn{calls: make(chan metadata.MD, 1)}
+	interceptor := NewMirrorInterceptor(MirrorOptions{Conn: shadow, Percent: 100})
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), "traceparent", "tp"))
+	reply := &wrapperspb.StringValue{}
+	err := interceptor(ctx, "/example.Service/Get", &wrapperspb.StringValue{}, reply, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			reply.(*wrapperspb.StringValue).Value = "primary"
+			return nil
+		})
+	// 主调用结束即取消 ctx，镜像调用不受影响。
+	cancel()
+	if err != nil || reply.Value != "primary" {
+		t.Fatalf("unexpected primary result: %v %q", err, reply.Value)
+	}
+
+	select {
+	case md := <-shadow.calls:
+		if got := md.Get(constant.Shadow); len(got) != 1 || got[0] != "1" {
+			t.Fatalf("expected shadow flag, got %v", got)
+		}
+		if got := md.Get("traceparent"); len(got) != 1 {
+			t.Fatalf("expected outgoing metadata to be copied, got %v", md)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected mirrored call")
+	}
+	if md, _ := metadata.FromOutgoingContext(ctx); len(md.Get(constant.Shadow)) != 0 {
+		t.Fatalf("expected primary metadata to stay untouched")
+	}
+}
+
+func TestNewMirrorInterceptorSkipsUnlistedMethods(t *testing.T) {
+	shadow := &shadowConn{calls: make(chan metadata.MD, 1)}
+	interceptor := NewMirrorInterceptor(MirrorOptions{Conn: shadow, Percent: 100, Methods: []string{"/example.Service/Get"}})
+
+	_ = interceptor(context.Background(), "/example.Service/Delete", nil, &wrapperspb.StringValue{}, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+	select {
+	case <-shadow.calls:
+		t.Fatalf("expected unlisted method not to be mirrored")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestIsShadowRequest(t *testing.T) {
+	if !IsShadowRequest(service.WithShadow(context.Background())) {
+		t.Fatalf("expected shadow request")
+	}
+	if IsShadowRequest(context.Background()) {
+		t.Fatalf("expected normal request")
+	}
+	// 未经入口确认的入站 header 不被信任。
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.Shadow, "1"))
+	if IsShadowRequest(ctx) {
+		t.Fatalf("expected raw shadow header to be ignored")
+	}
+}
+
+// blockingShadowConn 在收到放行信号后才读取请求内容，模拟主调用返回后影子调用才序列化请求。
+type blockingShadowConn struct {
+	release chan struct{}
+	values  chan string
+}
+
+func (c *blockingShadowConn) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	<-c.release
+	c.values <- args.(*wrapperspb.StringValue).GetValue()
+	return nil
+}
+
+func (c *blockingShadowConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, nil
+}
+
+func TestNewMirrorInterceptorClonesRequest(t *testing.T) {
+	shadow := &blockingShadowConn{release: make(chan struct{}), values: make(chan string, 1)}
+	interceptor := NewMirrorInterceptor(MirrorOptions{Conn: shadow, Percent: 100})
+
+	req := &wrapperspb.StringValue{Value: "original"}
+	err := interceptor(context.Background(), "/example.Service/Get", req, &wrapperspb.StringValue{}, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected primary error: %v", err)
+	}
+	// 主调用返回后调用方复用请求对象，影子调用仍应看到原始内容（go test -race 下不应报告数据竞争）。
+	req.Value = "reused"
+	close(shadow.release)
+
+	select {
+	case got := <-shadow.values:
+		if got != "original" {
+			t.Fatalf("expected mirrored request to be a snapshot, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected mirrored call")
+	}
+}
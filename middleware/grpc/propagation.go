@@ -0,0 +1,155 @@
+package gm
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/fireflycore/go-micro/authz"
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultPropagationMaxValueBytes 是单个透传 metadata 值的默认上限。
+	DefaultPropagationMaxValueBytes = 4 << 10
+	// DefaultPropagationMaxTotalBytes 是单次调用透传 metadata 的默认总上限。
+	DefaultPropagationMaxTotalBytes = 8 << 10
+)
+
+// MetadataPropagationOptions 定义入站 metadata 透传到出站的规则。
+type MetadataPropagationOptions struct {
+	// Keys 表示允许透传的 metadata key，大小写不敏感；为空时使用 authz.OutgoingMetadataAllowlist。
+	Keys []string
+	// MaxValueBytes 表示单个值的字节上限，超出的值直接丢弃；为 0 时使用默认值，负数表示不限制。
+	MaxValueBytes int
+	// MaxTotalBytes 表示透传 key 与值的总字节上限，按 key 排序依次累加，超出后的 key 整体丢弃；
+	// 为 0 时使用默认值，负数表示不限制。
+	MaxTotalBytes int
+}
+
+func (o MetadataPropagationOptions) normalize() MetadataPropagationOptions {
+	if len(o.Keys) == 0 {
+		o.Keys = authz.OutgoingMetadataAllowlist()
+	}
+	if o.MaxValueBytes == 0 {
+		o.MaxValueBytes = DefaultPropagationMaxValueBytes
+	}
+	if o.MaxTotalBytes == 0 {
+		o.MaxTotalBytes = DefaultPropagationMaxTotalBytes
+	}
+	return o
+}
+
+// metadataPropagator 持有预处理后的透传规则。
+type metadataPropagator struct {
+	keys          []string
+	maxValueBytes int
+	maxTotalBytes int
+}
+
+func newMetadataPropagator(options MetadataPropagationOptions) *metadataPropagator {
+	options = options.normalize()
+	seen := make(map[string]struct{}, len(options.Keys))
+	keys := make([]string, 0, len(options.Keys))
+	for _, key := range options.Keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	// 排序保证总量超限时丢弃哪些 key 是确定的。
+	sort.Strings(keys)
+	return &metadataPropagator{keys: keys, maxValueBytes: options.MaxValueBytes, maxTotalBytes: options.MaxTotalBytes}
+}
+
+// propagate 把入站 metadata 中允许透传的 key 合并进出站 metadata；
+// 出站已显式设置的 key 以调用方为准，不被入站值覆盖。
+//
+// 镜像标记不从入站 metadata 复制，只按 service.IsShadow 的可信标记写入。
+func (p *metadataPropagator) propagate(ctx context.Context) context.Context {
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	shadow := service.IsShadow(ctx)
+	if len(incoming) == 0 && !shadow {
+		return ctx
+	}
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	outgoing = outgoing.Copy()
+
+	total := 0
+	changed := false
+	if shadow && len(outgoing.Get(constant.Shadow)) == 0 {
+		outgoing.Set(constant.Shadow, "1")
+		changed = true
+	}
+	for _, key := range p.keys {
+		if key == constant.Shadow || len(outgoing.Get(key)) > 0 {
+			continue
+		}
+		values := p.acceptValues(incoming.Get(key))
+		if len(values) == 0 {
+			continue
+		}
+		size := len(key)
+		for _, value := range values {
+			size += len(value)
+		}
+		if p.maxTotalBytes > 0 && total+size > p.maxTotalBytes {
+			continue
+		}
+		total += size
+		outgoing[key] = values
+		changed = true
+	}
+	if !changed {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, outgoing)
+}
+
+// acceptValues 过滤超过单值上限的值，返回新切片避免共享入站 metadata。
+func (p *metadataPropagator) acceptValues(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	accepted := make([]string, 0, len(values))
+	for _, value := range values {
+		if p.maxValueBytes > 0 && len(value) > p.maxValueBytes {
+			continue
+		}
+		accepted = append(accepted, value)
+	}
+	return accepted
+}
+
+// PropagateMetadata 按 options 把入站 metadata 的白名单 key 复制到出站 ctx，
+// 适用于不经过拦截器的出站场景（例如在 handler 中手动构造下游 ctx）。
+func PropagateMetadata(ctx context.Context, options MetadataPropagationOptions) context.Context {
+	return newMetadataPropagator(options).propagate(ctx)
+}
+
+// NewMetadataPropagationInterceptor 创建 metadata 透传 unary 客户端拦截器。
+//
+// 只复制白名单内的入站 key，未知 key 与超过大小限制的值会被丢弃，
+// 避免 header 随调用链膨胀，也避免把仅对上一跳有意义的数据泄露到下游。
+func NewMetadataPropagationInterceptor(options MetadataPropagationOptions) grpc.UnaryClientInterceptor {
+	propagator := newMetadataPropagator(options)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(propagator.propagate(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// NewMetadataPropagationStreamInterceptor 是 NewMetadataPropagationInterceptor 的 stream 版本。
+func NewMetadataPropagationStreamInterceptor(options MetadataPropagationOptions) grpc.StreamClientInterceptor {
+	propagator := newMetadataPropagator(options)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(propagator.propagate(ctx), desc, cc, method, opts...)
+	}
+}
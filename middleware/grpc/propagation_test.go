@@ -0,0 +1,78 @@
+package gm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestPropagateMetadataCopiesAllowlistedKeys(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		constant.TraceParent, "00-abc-def-01",
+		constant.Shadow, "true",
+		constant.UserId, "user-1",
+		"x-unknown", "v",
+	))
+	ctx = metadata.AppendToOutgoingContext(ctx, constant.Shadow, "false")
+
+	out, _ := metadata.FromOutgoingContext(PropagateMetadata(ctx, MetadataPropagationOptions{}))
+	if got := out.Get(constant.TraceParent); len(got) != 1 || got[0] != "00-abc-def-01" {
+		t.Fatalf("expected traceparent to propagate, got %v", got)
+	}
+	if got := out.Get(constant.Shadow); len(got) != 1 || got[0] != "false" {
+		t.Fatalf("expected explicit outgoing value to win, got %v", got)
+	}
+	if len(out.Get(constant.UserId)) != 0 || len(out.Get("x-unknown")) != 0 {
+		t.Fatalf("expected non-allowlisted keys to be dropped: %v", out)
+	}
+}
+
+func TestPropagateMetadataOnlyForwardsTrustedShadowFlag(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.Shadow, "1"))
+	options := MetadataPropagationOptions{Keys: []string{constant.Shadow, constant.TraceParent}}
+
+	// 未经入口信任的入站标记不透传。
+	out, _ := metadata.FromOutgoingContext(PropagateMetadata(ctx, options))
+	if got := out.Get(constant.Shadow); len(got) != 0 {
+		t.Fatalf("expected untrusted shadow flag to be dropped, got %v", got)
+	}
+
+	out, _ = metadata.FromOutgoingContext(PropagateMetadata(service.WithShadow(context.Background()), MetadataPropagationOptions{}))
+	if got := out.Get(constant.Shadow); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("expected trusted shadow flag to propagate, got %v", got)
+	}
+}
+
+func TestPropagateMetadataDropsOversizedValues(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-firefly-a", "short",
+		"x-firefly-b", strings.Repeat("b", 64),
+		"x-firefly-c", strings.Repeat("c", 20),
+	))
+
+	out, _ := metadata.FromOutgoingContext(PropagateMetadata(ctx, MetadataPropagationOptions{
+		Keys:          []string{"X-Firefly-A", "x-firefly-b", "x-firefly-c"},
+		MaxValueBytes: 32,
+		MaxTotalBytes: 40,
+	}))
+	if got := out.Get("x-firefly-a"); len(got) != 1 || got[0] != "short" {
+		t.Fatalf("expected small value to propagate, got %v", got)
+	}
+	if len(out.Get("x-firefly-b")) != 0 {
+		t.Fatalf("expected oversized value to be dropped: %v", out)
+	}
+	if len(out.Get("x-firefly-c")) != 0 {
+		t.Fatalf("expected key exceeding total budget to be dropped: %v", out)
+	}
+}
+
+func TestPropagateMetadataWithoutIncoming(t *testing.T) {
+	ctx := context.Background()
+	if PropagateMetadata(ctx, MetadataPropagationOptions{}) != ctx {
+		t.Fatal("expected ctx to be returned unchanged without incoming metadata")
+	}
+}
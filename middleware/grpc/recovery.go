@@ -0,0 +1,65 @@
+package gm
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryOptions 定义 panic 恢复拦截器配置。
+type RecoveryOptions struct {
+	// Logger 用于记录 panic 值与堆栈；为空时只转换错误，不输出日志。
+	Logger *logger.ServerLogger
+	// Handler 用于把 panic 值转换为返回给调用方的错误；为空时统一返回 codes.Internal。
+	Handler func(ctx context.Context, recovered any) error
+}
+
+// NewRecoveryUnaryInterceptor 创建 panic 恢复 unary 拦截器。
+//
+// handler 发生 panic 时记录堆栈（带 trace_id 等上下文字段），并返回 codes.Internal，
+// 避免单个请求的 bug 导致整个进程退出。应放在拦截器链最外层，以覆盖其它拦截器的 panic。
+func NewRecoveryUnaryInterceptor(options RecoveryOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				resp, err = nil, recoverPanic(ctx, info.FullMethod, recovered, options)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// NewRecoveryStreamInterceptor 创建 panic 恢复 stream 拦截器，行为与 unary 版本一致。
+func NewRecoveryStreamInterceptor(options RecoveryOptions) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ss.Context(), info.FullMethod, recovered, options)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic 记录 panic 并转换为 gRPC 错误。
+func recoverPanic(ctx context.Context, method string, recovered any, options RecoveryOptions) error {
+	if options.Logger != nil {
+		options.Logger.WithContextError(ctx, constant.GrpcPanicLog,
+			zap.String("method", method),
+			zap.String("panic", fmt.Sprint(recovered)),
+			zap.ByteString("stack", debug.Stack()),
+		)
+	}
+	if options.Handler != nil {
+		return options.Handler(ctx, recovered)
+	}
+	// 不把 panic 细节返回给调用方，避免泄露内部实现。
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,64 @@
+package gm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewRecoveryUnaryInterceptorConvertsPanic(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewRecoveryUnaryInterceptor(RecoveryOptions{Logger: logger.NewServerLogger(zap.New(core))})
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+		func(ctx context.Context, req any) (any, error) {
+			panic("boom")
+		})
+	if resp != nil || status.Code(err) != codes.Internal {
+		t.Fatalf("unexpected result: resp=%v err=%v", resp, err)
+	}
+
+	entries := observed.All()
+	if len(entries) != 1 || entries[0].Message != constant.GrpcPanicLog {
+		t.Fatalf("expected one panic log, got %v", entries)
+	}
+	fields := entries[0].ContextMap()
+	if fields["panic"] != "boom" || !strings.Contains(fields["stack"].(string), "recovery_test.go") {
+		t.Fatalf("unexpected panic fields: %v", fields)
+	}
+}
+
+func TestNewRecoveryStreamInterceptorUsesCustomHandler(t *testing.T) {
+	interceptor := NewRecoveryStreamInterceptor(RecoveryOptions{
+		Handler: func(ctx context.Context, recovered any) error {
+			return status.Error(codes.Unavailable, "retry later")
+		},
+	})
+
+	err := interceptor(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"},
+		func(srv any, stream grpc.ServerStream) error {
+			panic("boom")
+		})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// testServerStream 是只提供 Context 的最小 ServerStream 实现。
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
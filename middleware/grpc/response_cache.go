@@ -0,0 +1,250 @@
+package gm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// responseCacheKeyPrefix 是响应缓存 key 的统一前缀，便于与同一存储中的其它数据区分。
+const responseCacheKeyPrefix = "firefly:grpc-cache:"
+
+// DefaultResponseCacheMaxEntries 是进程内响应缓存的默认最大条目数。
+const DefaultResponseCacheMaxEntries = 10000
+
+// ResponseCacheStore 定义响应缓存存储，单实例可使用 NewMemoryResponseCacheStore，多实例共享时可基于 Redis 实现。
+type ResponseCacheStore interface {
+	// Get 读取缓存；不存在或已过期时第二个返回值为 false。
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 写入缓存并设置 TTL。
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix 删除所有以 prefix 开头的缓存，用于失效整个方法或租户的缓存。
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// ResponseCacheOptions 定义响应缓存配置。
+type ResponseCacheOptions struct {
+	// Store 表示缓存存储；为空时使用进程内存储。
+	Store ResponseCacheStore
+	// Methods 表示启用缓存的完整方法名及其 TTL，只应配置幂等的读方法；未配置的方法不缓存。
+	//
+	// 默认按认证主体隔离缓存，避免 GetMyProfile 这类按用户返回的读方法把一个用户的响应返回给另一个用户。
+	Methods map[string]time.Duration
+	// TenantSharedMethods 表示响应只取决于租户与请求报文、可在同租户主体间共享缓存的完整方法名，
+	// 例如租户级配置查询；只有显式列出的方法才会省略认证主体。
+	TenantSharedMethods []string
+}
+
+func (o ResponseCacheOptions) normalize() ResponseCacheOptions {
+	if o.Store == nil {
+		o.Store = NewMemoryResponseCacheStore(DefaultResponseCacheMaxEntries)
+	}
+	return o
+}
+
+// ResponseCache 是按方法启用的服务端响应缓存。
+//
+// 缓存 key 由方法名、租户 ID、认证主体（TenantSharedMethods 除外）与确定性序列化后的请求摘要组成，
+// 只缓存成功且为 proto 消息的响应。
+// 存储读写失败时直接回退到调用 handler，不影响业务结果。
+type ResponseCache struct {
+	options ResponseCacheOptions
+	// shared 表示同租户内共享缓存、不区分认证主体的方法。
+	shared map[string]struct{}
+	// types 记录每个方法的响应消息类型，用于把缓存字节还原为响应对象。
+	types sync.Map
+}
+
+// NewResponseCache 创建响应缓存。
+func NewResponseCache(options ResponseCacheOptions) *ResponseCache {
+	options = options.normalize()
+	return &ResponseCache{options: options, shared: buildMethodSet(options.TenantSharedMethods)}
+}
+
+// UnaryInterceptor 返回响应缓存 unary 拦截器，需放在 NewServiceContextUnaryInterceptor 之后以区分租户与认证主体。
+func (c *ResponseCache) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := unaryFullMethod(info)
+		ttl, ok := c.options.Methods[method]
+		if !ok || ttl <= 0 {
+			return handler(ctx, req)
+		}
+		message, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		key, err := responseCacheKey(method, tenantIdFromContext(ctx), c.subject(ctx, method), message)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		if resp, hit := c.load(ctx, method, key); hit {
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		c.store(ctx, method, key, resp, ttl)
+		return resp, nil
+	}
+}
+
+// Invalidate 失效指定租户与认证主体下某个请求的缓存。
+//
+// subject 与拦截器生成 key 时一致：用户为 "user:<user_id>"，服务为 "service:<app_id>"；
+// TenantSharedMethods 中的方法忽略 subject。
+func (c *ResponseCache) Invalidate(ctx context.Context, method string, tenantId string, subject string, req proto.Message) error {
+	if _, ok := c.shared[method]; ok {
+		subject = ""
+	}
+	key, err := responseCacheKey(method, tenantId, subject, req)
+	if err != nil {
+		return err
+	}
+	return c.options.Store.DeletePrefix(ctx, key)
+}
+
+// InvalidateTenant 失效指定租户下某个方法的全部缓存。
+func (c *ResponseCache) InvalidateTenant(ctx context.Context, method string, tenantId string) error {
+	return c.options.Store.DeletePrefix(ctx, responseCacheMethodPrefix(method)+tenantId+"|")
+}
+
+// InvalidateMethod 失效某个方法的全部缓存。
+func (c *ResponseCache) InvalidateMethod(ctx context.Context, method string) error {
+	return c.options.Store.DeletePrefix(ctx, responseCacheMethodPrefix(method))
+}
+
+// subject 返回缓存 key 使用的认证主体；同租户共享的方法返回空字符串。
+func (c *ResponseCache) subject(ctx context.Context, method string) string {
+	if _, ok := c.shared[method]; ok {
+		return ""
+	}
+	return subjectFromContext(ctx)
+}
+
+// load 读取并还原缓存响应；响应类型未知（例如进程重启后首次命中共享缓存）时按未命中处理。
+func (c *ResponseCache) load(ctx context.Context, method string, key string) (any, bool) {
+	value, ok := c.types.Load(method)
+	if !ok {
+		return nil, false
+	}
+	data, hit, err := c.options.Store.Get(ctx, key)
+	if err != nil || !hit {
+		return nil, false
+	}
+	resp := value.(protoreflect.MessageType).New().Interface()
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+// store 序列化并写入响应。
+func (c *ResponseCache) store(ctx context.Context, method string, key string, resp any, ttl time.Duration) {
+	message, ok := resp.(proto.Message)
+	if !ok || message == nil {
+		return
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return
+	}
+	c.types.LoadOrStore(method, message.ProtoReflect().Type())
+	_ = c.options.Store.Set(ctx, key, data, ttl)
+}
+
+// responseCacheMethodPrefix 返回某个方法全部缓存的 key 前缀。
+func responseCacheMethodPrefix(method string) string {
+	return responseCacheKeyPrefix + method + "|"
+}
+
+// responseCacheKey 使用确定性序列化生成请求摘要，保证字段顺序与 map 顺序不影响 key。
+func responseCacheKey(method string, tenantId string, subject string, req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return responseCacheMethodPrefix(method) + tenantId + "|" + subject + "|" + hex.EncodeToString(sum[:]), nil
+}
+
+// memoryResponseCacheEntry 是进程内缓存条目。
+type memoryResponseCacheEntry struct {
+	value    []byte
+	expireAt time.Time
+}
+
+// MemoryResponseCacheStore 是进程内响应缓存存储。
+type MemoryResponseCacheStore struct {
+	mu         sync.Mutex
+	entries    map[string]memoryResponseCacheEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewMemoryResponseCacheStore 创建进程内响应缓存存储；maxEntries 非正数时使用默认值。
+func NewMemoryResponseCacheStore(maxEntries int) *MemoryResponseCacheStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultResponseCacheMaxEntries
+	}
+	return &MemoryResponseCacheStore{
+		entries:    make(map[string]memoryResponseCacheEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get 实现 ResponseCacheStore。
+func (s *MemoryResponseCacheStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !s.now().Before(entry.expireAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set 实现 ResponseCacheStore；达到容量上限且清理过期条目后仍无空间时放弃写入。
+func (s *MemoryResponseCacheStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= s.maxEntries {
+		for existing, entry := range s.entries {
+			if !now.Before(entry.expireAt) {
+				delete(s.entries, existing)
+			}
+		}
+		if len(s.entries) >= s.maxEntries {
+			return nil
+		}
+	}
+	s.entries[key] = memoryResponseCacheEntry{value: value, expireAt: now.Add(ttl)}
+	return nil
+}
+
+// DeletePrefix 实现 ResponseCacheStore。
+func (s *MemoryResponseCacheStore) DeletePrefix(_ context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResponseCacheHitsAndInvalidates(t *testing.T) {
+	store := NewMemoryResponseCacheStore(0)
+	now := time.Unix(1710000000, 0)
+	store.now = func() time.Time { return now }
+	cache := NewResponseCache(ResponseCacheOptions{
+		Store:   store,
+		Methods: map[string]time.Duration{"/acme.config.v1.ConfigService/Get": time.Minute},
+	})
+	interceptor := cache.UnaryInterceptor()
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return wrapperspb.String("value-" + req.(*wrapperspb.StringValue).GetValue()), nil
+	}
+	call := func(ctx context.Context, method string, key string) string {
+		resp, err := interceptor(ctx, wrapperspb.String(key), &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.(*wrapperspb.StringValue).GetValue()
+	}
+
+	tenantA := service.WithContext(context.Background(), &service.Context{TenantId: "a"})
+	tenantB := service.WithContext(context.Background(), &service.Context{TenantId: "b"})
+	const method = "/acme.config.v1.ConfigService/Get"
+
+	if got := call(tenantA, method, "k"); got != "value-k" {
+		t.Fatalf("unexpected response: %s", got)
+	}
+	if got := call(tenantA, method, "k"); got != "value-k" || calls != 1 {
+		t.Fatalf("expected cache hit, got %s after %d calls", got, calls)
+	}
+	call(tenantB, method, "k")
+	if calls != 2 {
+		t.Fatalf("expected tenants to be cached separately, got %d calls", calls)
+	}
+	call(tenantA, "/acme.config.v1.ConfigService/List", "k")
+	call(tenantA, "/acme.config.v1.ConfigService/List", "k")
+	if calls != 4 {
+		t.Fatalf("expected non-configured method to bypass cache, got %d calls", calls)
+	}
+
+	if err := cache.Invalidate(context.Background(), method, "a", "", wrapperspb.String("k")); err != nil {
+		t.Fatalf("invalidate failed: %v", err)
+	}
+	call(tenantA, method, "k")
+	call(tenantB, method, "k")
+	if calls != 5 {
+		t.Fatalf("expected only tenant a entry to be invalidated, got %d calls", calls)
+	}
+
+	if err := cache.InvalidateMethod(context.Background(), method); err != nil {
+		t.Fatalf("invalidate method failed: %v", err)
+	}
+	call(tenantB, method, "k")
+	if calls != 6 {
+		t.Fatalf("expected method invalidation, got %d calls", calls)
+	}
+
+	now = now.Add(time.Minute)
+	call(tenantB, method, "k")
+	if calls != 7 {
+		t.Fatalf("expected entry to expire after ttl, got %d calls", calls)
+	}
+}
+
+func TestResponseCacheIsolatesSubjects(t *testing.T) {
+	const (
+		profileMethod = "/acme.user.v1.UserService/GetMyProfile"
+		configMethod  = "/acme.config.v1.ConfigService/Get"
+	)
+	cache := NewResponseCache(ResponseCacheOptions{
+		Methods:             map[string]time.Duration{profileMethod: time.Minute, configMethod: time.Minute},
+		TenantSharedMethods: []string{configMethod},
+	})
+	interceptor := cache.UnaryInterceptor()
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return wrapperspb.String(subjectFromContext(ctx)), nil
+	}
+	call := func(ctx context.Context, method string) string {
+		resp, err := interceptor(ctx, wrapperspb.String(""), &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.(*wrapperspb.StringValue).GetValue()
+	}
+
+	alice := service.WithContext(context.Background(), &service.Context{TenantId: "a", UserId: "alice"})
+	bob := service.WithContext(context.Background(), &service.Context{TenantId: "a", UserId: "bob"})
+
+	// 请求报文相同的按用户读方法不会把 alice 的响应返回给 bob。
+	call(alice, profileMethod)
+	if got := call(bob, profileMethod); got != "user:bob" || calls != 2 {
+		t.Fatalf("expected per-subject cache entries, got %q after %d calls", got, calls)
+	}
+	if got := call(alice, profileMethod); got != "user:alice" || calls != 2 {
+		t.Fatalf("expected alice to hit her own entry, got %q after %d calls", got, calls)
+	}
+
+	// 显式声明为租户共享的方法在同租户主体间共享。
+	call(alice, configMethod)
+	call(bob, configMethod)
+	if calls != 3 {
+		t.Fatalf("expected tenant shared method to be cached across subjects, got %d calls", calls)
+	}
+
+	if err := cache.Invalidate(context.Background(), profileMethod, "a", "user:bob", wrapperspb.String("")); err != nil {
+		t.Fatalf("invalidate failed: %v", err)
+	}
+	call(alice, profileMethod)
+	call(bob, profileMethod)
+	if calls != 4 {
+		t.Fatalf("expected only bob's entry to be invalidated, got %d calls", calls)
+	}
+}
@@ -0,0 +1,164 @@
+package gm
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultRetryMaxAttempts 是包含首次请求在内的默认最大请求次数。
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoff 是第一次重试前的默认退避时长。
+	DefaultRetryInitialBackoff = 50 * time.Millisecond
+	// DefaultRetryMaxBackoff 是单次退避的默认上限。
+	DefaultRetryMaxBackoff = time.Second
+	// maxRetryAttempts 是最大请求次数上限，避免配置失误放大下游流量。
+	maxRetryAttempts = 5
+)
+
+// DefaultRetryCodes 是客户端重试拦截器默认视为可重试的 gRPC code。
+//
+// DeadlineExceeded 只在单次请求超时（PerTryTimeout）且整体 deadline 仍有余量时重试。
+var DefaultRetryCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded}
+
+// DefaultIdempotentPrefixes 是按命名约定视为幂等读接口的方法名前缀。
+var DefaultIdempotentPrefixes = []string{"Get", "List", "Search", "Query", "Count", "Check", "BatchGet"}
+
+// RetryOptions 定义客户端重试拦截器配置。
+type RetryOptions struct {
+	// MaxAttempts 表示包含首次请求在内的最大请求次数；未设置时使用 DefaultRetryMaxAttempts，上限为 5。
+	MaxAttempts int
+	// InitialBackoff 表示第一次重试前的退避时长，之后按 2 倍指数增长并加入随机抖动。
+	InitialBackoff time.Duration
+	// MaxBackoff 表示单次退避上限，同时限制服务端 RetryAfter 提示的等待时长。
+	MaxBackoff time.Duration
+	// PerTryTimeout 表示单次请求超时；0 表示每次请求共享调用方的整体 deadline。
+	PerTryTimeout time.Duration
+	// Codes 表示可重试的 gRPC code；服务端通过 rpcerror.WithRetryHint 给出提示时以提示为准。
+	Codes []codes.Code
+	// Methods 表示显式允许重试的 full method，非幂等方法需要在这里显式开启。
+	Methods []string
+	// IdempotentPrefixes 表示按命名约定视为幂等的方法名前缀；未设置时使用 DefaultIdempotentPrefixes。
+	IdempotentPrefixes []string
+}
+
+// normalize 补齐默认值。
+func (o RetryOptions) normalize() RetryOptions {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if o.MaxAttempts > maxRetryAttempts {
+		o.MaxAttempts = maxRetryAttempts
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if len(o.Codes) == 0 {
+		o.Codes = DefaultRetryCodes
+	}
+	if o.IdempotentPrefixes == nil {
+		o.IdempotentPrefixes = DefaultIdempotentPrefixes
+	}
+	return o
+}
+
+// retryable 判断方法是否允许重试：显式开启或方法名符合幂等命名约定。
+func (o RetryOptions) retryable(methods map[string]struct{}, fullMethod string) bool {
+	if _, ok := methods[fullMethod]; ok {
+		return true
+	}
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range o.IdempotentPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRetryInterceptor 创建客户端重试 unary 拦截器。
+//
+// 只有幂等方法（命名约定或 Methods 显式开启）才会重试；是否可重试优先读取服务端
+// rpcerror 重试提示，否则按 Codes 分类。重试请求携带 x-firefly-retry-attempt，
+// 退避时长取指数退避与服务端 RetryAfter 中的较大值，RetryAfter 不超过 MaxBackoff，且不会超过调用方的整体 deadline。
+func NewRetryInterceptor(options RetryOptions) grpc.UnaryClientInterceptor {
+	options = options.normalize()
+	methods := make(map[string]struct{}, len(options.Methods))
+	for _, method := range options.Methods {
+		methods[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !options.retryable(methods, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		backoff := options.InitialBackoff
+		var err error
+		for attempt := 0; attempt < options.MaxAttempts; attempt++ {
+			if attempt > 0 {
+				retryable, retryAfter := rpcerror.IsRetryable(err, options.Codes...)
+				// 调用方自身取消或整体超时时不再重试；未设置 PerTryTimeout 时 DeadlineExceeded 只可能来自共享的整体 deadline。
+				if !retryable || ctx.Err() != nil || (options.PerTryTimeout <= 0 && status.Code(err) == codes.DeadlineExceeded) {
+					return err
+				}
+				// 服务端 RetryAfter 同样受 MaxBackoff 约束，避免异常服务端在调用方没有 deadline 时无限期阻塞调用方。
+				wait := max(jitter(backoff), min(retryAfter, options.MaxBackoff))
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+					return err
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+				backoff = min(backoff*2, options.MaxBackoff)
+			}
+			err = invokeAttempt(ctx, attempt, options.PerTryTimeout, method, req, reply, cc, invoker, opts)
+			if err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// invokeAttempt 发起单次请求，重试请求追加重试序号并施加单次超时。
+func invokeAttempt(ctx context.Context, attempt int, perTryTimeout time.Duration, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts []grpc.CallOption) error {
+	if attempt > 0 {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set(constant.RetryAttempt, strconv.Itoa(attempt))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	if perTryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, perTryTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// jitter 在 [d/2, d] 范围内随机化退避时长，避免大量客户端同时重试。
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half+1)
+}
@@ -0,0 +1,131 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryInterceptorRetriesIdempotentMethodWithAttemptMetadata(t *testing.T) {
+	interceptor := NewRetryInterceptor(RetryOptions{InitialBackoff: time.Millisecond})
+
+	var attempts []string
+	err := interceptor(context.Background(), "/acme.order.v1.OrderService/GetOrder", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			attempts = append(attempts, firstValue(md.Get(constant.RetryAttempt)))
+			if len(attempts) < 3 {
+				return status.Error(codes.Unavailable, "down")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attempts) != 3 || attempts[0] != "" || attempts[1] != "1" || attempts[2] != "2" {
+		t.Fatalf("unexpected attempts: %v", attempts)
+	}
+}
+
+func TestRetryInterceptorSkipsNonIdempotentMethodAndRespectsHints(t *testing.T) {
+	interceptor := NewRetryInterceptor(RetryOptions{
+		InitialBackoff: time.Millisecond,
+		Methods:        []string{"/acme.order.v1.OrderService/Charge"},
+	})
+
+	calls := 0
+	unavailable := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	// 非幂等方法默认不重试。
+	_ = interceptor(context.Background(), "/acme.order.v1.OrderService/CreateOrder", nil, nil, nil, unavailable)
+	if calls != 1 {
+		t.Fatalf("expected non-idempotent method to be called once, got %d", calls)
+	}
+
+	// 显式开启的方法遵循服务端"不可重试"提示。
+	calls = 0
+	_ = interceptor(context.Background(), "/acme.order.v1.OrderService/Charge", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return rpcerror.WithRetryHint(status.Error(codes.Unavailable, "draining"), rpcerror.RetryHint{Retryable: false})
+		})
+	if calls != 1 {
+		t.Fatalf("expected retry hint to stop retries, got %d calls", calls)
+	}
+}
+
+func TestRetryInterceptorRetriesPerTryTimeout(t *testing.T) {
+	interceptor := NewRetryInterceptor(RetryOptions{
+		InitialBackoff: time.Millisecond,
+		PerTryTimeout:  10 * time.Millisecond,
+	})
+
+	calls := 0
+	err := interceptor(context.Background(), "/acme.order.v1.OrderService/ListOrders", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return nil
+		})
+	if err != nil || calls != 2 {
+		t.Fatalf("expected per-try timeout to be retried: err=%v calls=%d", err, calls)
+	}
+}
+
+func TestRetryInterceptorSkipsDeadlineExceededWithoutPerTryTimeout(t *testing.T) {
+	interceptor := NewRetryInterceptor(RetryOptions{InitialBackoff: time.Millisecond})
+
+	calls := 0
+	err := interceptor(context.Background(), "/acme.order.v1.OrderService/ListOrders", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.DeadlineExceeded, "slow")
+		})
+	if status.Code(err) != codes.DeadlineExceeded || calls != 1 {
+		t.Fatalf("expected DeadlineExceeded not to be retried without PerTryTimeout: err=%v calls=%d", err, calls)
+	}
+}
+
+func TestRetryInterceptorCapsRetryAfterAtMaxBackoff(t *testing.T) {
+	interceptor := NewRetryInterceptor(RetryOptions{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+
+	calls := 0
+	started := time.Now()
+	err := interceptor(context.Background(), "/acme.order.v1.OrderService/ListOrders", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				return rpcerror.WithRetryHint(status.Error(codes.Unavailable, "busy"), rpcerror.RetryHint{Retryable: true, RetryAfter: time.Hour})
+			}
+			return nil
+		})
+	if err != nil || calls != 2 {
+		t.Fatalf("expected retry after capped wait: err=%v calls=%d", err, calls)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected RetryAfter to be capped by MaxBackoff, waited %v", elapsed)
+	}
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -2,6 +2,7 @@ package gm
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/fireflycore/go-micro/constant"
 	"github.com/fireflycore/go-micro/service"
@@ -34,24 +35,106 @@ func NewServiceContextUnaryInterceptor(options ServiceContextInterceptorOptions)
 	skipAuthzMethods := buildServiceContextAuthzSkipMethods(options.AuthzSkipMethods)
 
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		// 先把当前服务自身身份写入本地 incoming metadata，供 gormx 等只读 metadata 的组件使用。
-		ctx = appendLocalServiceIdentityToIncomingContext(ctx, options.ServiceAppId, options.ServiceInstanceId)
-		// 根据配置决定只结构化上下文，还是结构化后再校验 authz JWS。
-		serviceContext, err := buildServiceContext(ctx, info, buildOptions, options.AuthzVerification, skipAuthzMethods)
+		ctx, err := injectServiceContext(ctx, unaryFullMethod(info), options, buildOptions, skipAuthzMethods)
 		if err != nil {
-			// 验签失败说明入口身份不可被信任，统一返回 Unauthenticated。
-			return nil, status.Error(codes.Unauthenticated, err.Error())
+			return nil, err
 		}
-		// 构建成功后把 service.Context 注入 ctx，业务层统一从 service.FromContext 读取。
-		if serviceContext != nil {
-			ctx = service.WithContext(ctx, serviceContext)
-		}
-
 		// 继续执行后续拦截器或业务 handler。
 		return handler(ctx, req)
 	}
 }
 
+// NewServiceContextStreamInterceptor 是 NewServiceContextUnaryInterceptor 的 stream 版本，
+// 建立与验签逻辑完全一致，handler 通过 stream.Context() 读取 service.Context。
+func NewServiceContextStreamInterceptor(options ServiceContextInterceptorOptions) grpc.StreamServerInterceptor {
+	buildOptions := service.BuildContextOptions{
+		ServiceAppId:      options.ServiceAppId,
+		ServiceInstanceId: options.ServiceInstanceId,
+	}
+	skipAuthzMethods := buildServiceContextAuthzSkipMethods(options.AuthzSkipMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := injectServiceContext(ss.Context(), streamFullMethod(info), options, buildOptions, skipAuthzMethods)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// injectServiceContext 是 unary 与 stream 拦截器共用的入口上下文构建逻辑。
+func injectServiceContext(ctx context.Context, fullMethod string, options ServiceContextInterceptorOptions, buildOptions service.BuildContextOptions, skipAuthzMethods map[string]struct{}) (context.Context, error) {
+	// 先把当前服务自身身份写入本地 incoming metadata，供 gormx 等只读 metadata 的组件使用。
+	ctx = appendLocalServiceIdentityToIncomingContext(ctx, options.ServiceAppId, options.ServiceInstanceId)
+	// 根据配置决定只结构化上下文，还是结构化后再校验 authz JWS。
+	serviceContext, err := buildServiceContext(ctx, fullMethod, buildOptions, options.AuthzVerification, skipAuthzMethods)
+	if err != nil {
+		// 验签失败说明入口身份不可被信任，统一返回 Unauthenticated。
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	// 构建成功后把 service.Context 注入 ctx，业务层统一从 service.FromContext 读取。
+	if serviceContext != nil {
+		ctx = service.WithContext(ctx, serviceContext)
+		// 外层访问日志无法读取内层 ctx，通过其安装的容器回传结构化上下文。
+		if holder, ok := ctx.Value(serviceContextHolderKey{}).(*serviceContextHolder); ok {
+			holder.value.Store(serviceContext)
+		}
+	}
+	return ctx, nil
+}
+
+// serviceContextHolderKey 是 service.Context 回传容器在 ctx 中的 key。
+type serviceContextHolderKey struct{}
+
+// serviceContextHolder 保存内层拦截器构建出的 service.Context，供位于其外层的访问日志读取。
+type serviceContextHolder struct {
+	value atomic.Pointer[service.Context]
+}
+
+// withServiceContextHolder 在 ctx 中安装回传容器；已安装时复用。
+func withServiceContextHolder(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(serviceContextHolderKey{}).(*serviceContextHolder); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, serviceContextHolderKey{}, &serviceContextHolder{})
+}
+
+// serviceContextForLog 读取访问日志使用的 service.Context：优先 ctx 中已注入的值，其次回传容器中的值。
+func serviceContextForLog(ctx context.Context) *service.Context {
+	if value, ok := service.FromContext(ctx); ok {
+		return value
+	}
+	if holder, ok := ctx.Value(serviceContextHolderKey{}).(*serviceContextHolder); ok {
+		return holder.value.Load()
+	}
+	return nil
+}
+
+// contextServerStream 用替换后的 ctx 包装 grpc.ServerStream。
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回拦截器注入后的 ctx。
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func unaryFullMethod(info *grpc.UnaryServerInfo) string {
+	if info == nil {
+		return ""
+	}
+	return info.FullMethod
+}
+
+func streamFullMethod(info *grpc.StreamServerInfo) string {
+	if info == nil {
+		return ""
+	}
+	return info.FullMethod
+}
+
 func appendLocalServiceIdentityToIncomingContext(ctx context.Context, serviceAppId string, serviceInstanceId string) context.Context {
 	// 没有本地服务身份配置时，直接返回原 ctx，避免制造空 metadata。
 	if serviceAppId == "" && serviceInstanceId == "" {
@@ -72,9 +155,9 @@ func appendLocalServiceIdentityToIncomingContext(ctx context.Context, serviceApp
 	return metadata.NewIncomingContext(ctx, md)
 }
 
-func buildServiceContext(ctx context.Context, info *grpc.UnaryServerInfo, buildOptions service.BuildContextOptions, verification *service.AuthzSignVerificationOptions, skipAuthzMethods map[string]struct{}) (*service.Context, error) {
+func buildServiceContext(ctx context.Context, fullMethod string, buildOptions service.BuildContextOptions, verification *service.AuthzSignVerificationOptions, skipAuthzMethods map[string]struct{}) (*service.Context, error) {
 	// 没有配置验签或当前方法明确跳过验签时，只构造进程内 service.Context。
-	if verification == nil || shouldSkipServiceContextAuthz(info, skipAuthzMethods) {
+	if verification == nil || shouldSkipServiceContextAuthz(fullMethod, skipAuthzMethods) {
 		return service.BuildContext(ctx, buildOptions), nil
 	}
 
@@ -85,8 +168,8 @@ func buildServiceContext(ctx context.Context, info *grpc.UnaryServerInfo, buildO
 		resolvedVerification.ExpectedApiMethod = constant.RequestMethodGrpcString
 	}
 	// 未显式配置资源路径时，使用当前 gRPC FullMethod 校验授权结果不可跨方法复用。
-	if resolvedVerification.ExpectedApiPath == "" && info != nil {
-		resolvedVerification.ExpectedApiPath = info.FullMethod
+	if resolvedVerification.ExpectedApiPath == "" && fullMethod != "" {
+		resolvedVerification.ExpectedApiPath = fullMethod
 	}
 
 	// 把本次请求解析出的期望值放回 buildOptions，交给 service 层完成实际验签。
@@ -115,13 +198,13 @@ func buildServiceContextAuthzSkipMethods(methods []string) map[string]struct{} {
 	return result
 }
 
-func shouldSkipServiceContextAuthz(info *grpc.UnaryServerInfo, methods map[string]struct{}) bool {
+func shouldSkipServiceContextAuthz(fullMethod string, methods map[string]struct{}) bool {
 	// 没有配置跳过集合或缺少 gRPC 方法信息时，不跳过验签。
-	if len(methods) == 0 || info == nil {
+	if len(methods) == 0 || fullMethod == "" {
 		return false
 	}
 	// FullMethod 命中集合时跳过验签，常见于 health check。
-	_, ok := methods[info.FullMethod]
+	_, ok := methods[fullMethod]
 	// 返回是否跳过当前方法。
 	return ok
 }
@@ -14,7 +14,9 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -201,6 +203,54 @@ func TestNewServiceContextUnaryInterceptor_VerifiesAuthzSign(t *testing.T) {
 	}
 }
 
+func TestNewServiceContextStreamInterceptorInjectsContext(t *testing.T) {
+	interceptor := NewServiceContextStreamInterceptor(ServiceContextInterceptorOptions{
+		ServiceAppId: "svc-app",
+	})
+
+	baseCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.UserId, "user-1"))
+	err := interceptor(nil, &testServerStream{ctx: baseCtx}, &grpc.StreamServerInfo{FullMethod: "/acme.test.v1.TestService/Watch"},
+		func(srv any, stream grpc.ServerStream) error {
+			value, ok := servicectx.FromContext(stream.Context())
+			if !ok {
+				t.Fatal("expected service context in stream context")
+			}
+			if value.ServiceAppId != "svc-app" || value.UserId != "user-1" {
+				t.Fatalf("unexpected service context: %+v", value)
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestNewServiceContextStreamInterceptorRejectsMissingAuthzSign(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+	interceptor := NewServiceContextStreamInterceptor(ServiceContextInterceptorOptions{
+		AuthzVerification: &servicectx.AuthzSignVerificationOptions{
+			PublicKeys: map[string]ed25519.PublicKey{testAuthzKid: publicKey},
+			Issuer:     testAuthzIssuer,
+		},
+	})
+
+	called := false
+	err = interceptor(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/acme.test.v1.TestService/Watch"},
+		func(srv any, stream grpc.ServerStream) error {
+			called = true
+			return nil
+		})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if called {
+		t.Fatal("expected handler not to be called")
+	}
+}
+
 func signTestAuthzSign(t *testing.T, privateKey ed25519.PrivateKey, kid string, claims map[string]any) string {
 	t.Helper()
 
@@ -0,0 +1,56 @@
+package gm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewRequestSizeLimitUnaryInterceptor(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewRequestSizeLimitUnaryInterceptor(RequestSizeLimitOptions{
+		MaxBytes:     16,
+		MethodLimits: map[string]int{"/acme.file.v1.FileService/Upload": 0},
+		Logger:       logger.NewServerLogger(zap.New(baseCore)),
+	})
+	call := func(method string, value string) error {
+		_, err := interceptor(context.Background(), wrapperspb.String(value), &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return "ok", nil })
+		return err
+	}
+
+	if err := call("/acme.order.v1.OrderService/Create", "small"); err != nil {
+		t.Fatalf("expected small request to pass, got %v", err)
+	}
+	if err := call("/acme.order.v1.OrderService/Create", strings.Repeat("x", 64)); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	if err := call("/acme.file.v1.FileService/Upload", strings.Repeat("x", 64)); err != nil {
+		t.Fatalf("expected unlimited method to pass, got %v", err)
+	}
+
+	entries := observed.All()
+	if len(entries) != 1 || entries[0].ContextMap()["size"] != int64(66) || entries[0].ContextMap()["limit"] != int64(16) {
+		t.Fatalf("unexpected rejection log: %v", entries)
+	}
+}
+
+func TestNewRequestSizeLimitStreamInterceptor(t *testing.T) {
+	interceptor := NewRequestSizeLimitStreamInterceptor(RequestSizeLimitOptions{MaxBytes: 16})
+	err := interceptor(nil, &countingServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/acme.order.v1.OrderService/Sync"},
+		func(srv any, stream grpc.ServerStream) error {
+			return stream.RecvMsg(wrapperspb.String(strings.Repeat("x", 64)))
+		})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for oversized stream message, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package gm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// pkgName 作为 otel instrumentation scope name 使用。
+const pkgName = "github.com/fireflycore/go-micro/middleware/grpc"
+
+// DefaultStreamStallTimeout 是流无进展多久后判定为 stall 的默认阈值。
+const DefaultStreamStallTimeout = 10 * time.Second
+
+const (
+	// streamDirectionSend 表示服务端向客户端发送。
+	streamDirectionSend = "send"
+	// streamDirectionRecv 表示服务端从客户端接收。
+	streamDirectionRecv = "recv"
+)
+
+// StreamFlowOptions 定义流式 RPC 流控观测配置。
+type StreamFlowOptions struct {
+	// StallTimeout 表示连续无收发进展多久后触发 stall 事件；未设置时使用 DefaultStreamStallTimeout。
+	StallTimeout time.Duration
+	// Logger 用于输出 stall 事件；为空时只记录指标。
+	Logger *logger.ServerLogger
+}
+
+// normalize 补齐默认值。
+func (o StreamFlowOptions) normalize() StreamFlowOptions {
+	if o.StallTimeout <= 0 {
+		o.StallTimeout = DefaultStreamStallTimeout
+	}
+	return o
+}
+
+// streamFlowMetrics 汇总流控相关指标。
+type streamFlowMetrics struct {
+	// messages 记录流消息数，按 method/direction 聚合。
+	messages metric.Int64Counter
+	// bytes 记录流消息字节数，按 method/direction 聚合。
+	bytes metric.Int64Counter
+	// sendDuration 记录单次 SendMsg 阻塞时长，发送窗口耗尽时会明显升高。
+	sendDuration metric.Float64Histogram
+	// throughput 记录单个流结束时的平均吞吐。
+	throughput metric.Float64Histogram
+	// stalls 记录 stall 事件次数，按 method/direction 聚合。
+	stalls metric.Int64Counter
+}
+
+// newStreamFlowMetrics 通过全局 MeterProvider 创建指标；创建失败时返回 noop 指标。
+func newStreamFlowMetrics() *streamFlowMetrics {
+	meter := otel.Meter(pkgName)
+	messages, _ := meter.Int64Counter("rpc.server.stream.messages",
+		metric.WithDescription("Number of messages sent or received on server streams."),
+	)
+	bytes, _ := meter.Int64Counter("rpc.server.stream.bytes",
+		metric.WithDescription("Number of bytes sent or received on server streams."),
+		metric.WithUnit("By"),
+	)
+	sendDuration, _ := meter.Float64Histogram("rpc.server.stream.send_duration",
+		metric.WithDescription("Time spent blocked in SendMsg, dominated by flow-control window waits."),
+		metric.WithUnit("ms"),
+	)
+	throughput, _ := meter.Float64Histogram("rpc.server.stream.throughput",
+		metric.WithDescription("Average bytes per second of a server stream over its lifetime."),
+		metric.WithUnit("By/s"),
+	)
+	stalls, _ := meter.Int64Counter("rpc.server.stream.stalls",
+		metric.WithDescription("Number of server streams that made no progress within the stall timeout."),
+	)
+	return &streamFlowMetrics{
+		messages:     messages,
+		bytes:        bytes,
+		sendDuration: sendDuration,
+		throughput:   throughput,
+		stalls:       stalls,
+	}
+}
+
+// NewStreamFlowInterceptor 创建流式 RPC 流控观测拦截器。
+//
+// 设计说明：
+// - 每次 SendMsg/RecvMsg 完成视为一次进展，并记录消息数与字节数；
+// - SendMsg 的阻塞时长单独记录，慢消费者导致发送窗口耗尽时会体现在该指标上；
+// - 连续 StallTimeout 无进展时输出一次 stall 事件，恢复进展后可以再次触发；
+// - stall 事件中 pending_send=true 表示卡在发送（慢消费者），否则表示双方都没有消息往来。
+func NewStreamFlowInterceptor(options StreamFlowOptions) grpc.StreamServerInterceptor {
+	options = options.normalize()
+	metrics := newStreamFlowMetrics()
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &flowServerStream{
+			ServerStream: ss,
+			metrics:      metrics,
+			method:       attribute.String(metricAttrRPCMethod, info.FullMethod),
+		}
+		start := time.Now()
+		stream.lastProgress.Store(start.UnixNano())
+
+		done := make(chan struct{})
+		go stream.watch(options, info.FullMethod, done)
+
+		err := handler(srv, stream)
+		close(done)
+
+		// 记录整个流生命周期内的平均吞吐。
+		if seconds := time.Since(start).Seconds(); seconds > 0 {
+			total := stream.sentBytes.Load() + stream.recvBytes.Load()
+			metrics.throughput.Record(context.Background(), float64(total)/seconds, metric.WithAttributes(stream.method))
+		}
+		return err
+	}
+}
+
+// flowServerStream 包装 grpc.ServerStream 以统计收发进展。
+type flowServerStream struct {
+	grpc.ServerStream
+	metrics *streamFlowMetrics
+	method  attribute.KeyValue
+
+	// lastProgress 记录最近一次收发完成的时间（UnixNano）。
+	lastProgress atomic.Int64
+	// pendingSend 表示当前是否阻塞在 SendMsg 中。
+	pendingSend atomic.Bool
+	sentCount   atomic.Int64
+	recvCount   atomic.Int64
+	sentBytes   atomic.Int64
+	recvBytes   atomic.Int64
+}
+
+// SendMsg 发送消息并记录进展。
+func (s *flowServerStream) SendMsg(m any) error {
+	s.pendingSend.Store(true)
+	start := time.Now()
+	err := s.ServerStream.SendMsg(m)
+	s.pendingSend.Store(false)
+
+	s.metrics.sendDuration.Record(context.Background(), float64(time.Since(start).Microseconds())/1000, metric.WithAttributes(s.method))
+	if err == nil {
+		s.progress(streamDirectionSend, m, &s.sentCount, &s.sentBytes)
+	}
+	return err
+}
+
+// RecvMsg 接收消息并记录进展。
+func (s *flowServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.progress(streamDirectionRecv, m, &s.recvCount, &s.recvBytes)
+	}
+	return err
+}
+
+// progress 更新进展时间与计数。
+func (s *flowServerStream) progress(direction string, m any, count, bytes *atomic.Int64) {
+	s.lastProgress.Store(time.Now().UnixNano())
+
+	size := 0
+	if message, ok := m.(proto.Message); ok {
+		size = proto.Size(message)
+	}
+	count.Add(1)
+	bytes.Add(int64(size))
+
+	attrs := metric.WithAttributes(s.method, attribute.String("direction", direction))
+	s.metrics.messages.Add(context.Background(), 1, attrs)
+	s.metrics.bytes.Add(context.Background(), int64(size), attrs)
+}
+
+// watch 周期检查流是否 stall，直到流结束。
+func (s *flowServerStream) watch(options StreamFlowOptions, fullMethod string, done <-chan struct{}) {
+	// 以阈值的一半作为检查周期，stall 最迟在 1.5 倍阈值内被发现。
+	ticker := time.NewTicker(options.StallTimeout / 2)
+	defer ticker.Stop()
+
+	// reported 记录已上报 stall 对应的进展时间，同一次 stall 只上报一次。
+	var reported int64
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			last := s.lastProgress.Load()
+			stalled := now.Sub(time.Unix(0, last))
+			if stalled < options.StallTimeout || last == reported {
+				continue
+			}
+			reported = last
+			s.reportStall(options, fullMethod, stalled)
+		}
+	}
+}
+
+// reportStall 输出 stall 指标与日志。
+func (s *flowServerStream) reportStall(options StreamFlowOptions, fullMethod string, stalled time.Duration) {
+	pendingSend := s.pendingSend.Load()
+	direction := streamDirectionRecv
+	if pendingSend {
+		direction = streamDirectionSend
+	}
+	s.metrics.stalls.Add(context.Background(), 1, metric.WithAttributes(s.method, attribute.String("direction", direction)))
+
+	if options.Logger == nil {
+		return
+	}
+	options.Logger.WithContextWarn(s.Context(), constant.GrpcStreamStallLog,
+		zap.String("path", fullMethod),
+		zap.Int64("stalled_ms", stalled.Milliseconds()),
+		zap.Bool("pending_send", pendingSend),
+		zap.Int64("sent_messages", s.sentCount.Load()),
+		zap.Int64("recv_messages", s.recvCount.Load()),
+		zap.Int64("sent_bytes", s.sentBytes.Load()),
+		zap.Int64("recv_bytes", s.recvBytes.Load()),
+	)
+}
@@ -0,0 +1,123 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// blockingServerStream 模拟慢消费者：SendMsg 阻塞到 release 关闭。
+type blockingServerStream struct {
+	grpc.ServerStream
+	release chan struct{}
+}
+
+func (s *blockingServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *blockingServerStream) SendMsg(m any) error {
+	<-s.release
+	return nil
+}
+
+func TestStreamFlowInterceptorReportsSendStall(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewStreamFlowInterceptor(StreamFlowOptions{
+		StallTimeout: 40 * time.Millisecond,
+		Logger:       logger.NewServerLogger(zap.New(baseCore)),
+	})
+
+	stream := &blockingServerStream{release: make(chan struct{})}
+	go func() {
+		// 等待足够长的时间让 watcher 发现 stall，再放行发送。
+		time.Sleep(150 * time.Millisecond)
+		close(stream.release)
+	}()
+
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"}, func(srv any, ss grpc.ServerStream) error {
+		return ss.SendMsg(wrapperspb.String("payload"))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := observed.FilterMessage(constant.GrpcStreamStallLog).All()
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly one stall event, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["pending_send"] != true {
+		t.Fatalf("expected pending_send=true, got %v", fields["pending_send"])
+	}
+	if fields["path"] != "/example.Service/Watch" {
+		t.Fatalf("unexpected path: %v", fields["path"])
+	}
+}
+
+func TestStreamFlowInterceptorSkipsActiveStream(t *testing.T) {
+	baseCore, observed := observer.New(zapcore.InfoLevel)
+	interceptor := NewStreamFlowInterceptor(StreamFlowOptions{
+		StallTimeout: 40 * time.Millisecond,
+		Logger:       logger.NewServerLogger(zap.New(baseCore)),
+	})
+
+	stream := &blockingServerStream{release: make(chan struct{})}
+	close(stream.release)
+
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"}, func(srv any, ss grpc.ServerStream) error {
+		for i := 0; i < 10; i++ {
+			if err := ss.SendMsg(wrapperspb.String("payload")); err != nil {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := observed.FilterMessage(constant.GrpcStreamStallLog).Len(); got != 0 {
+		t.Fatalf("expected no stall events, got %d", got)
+	}
+}
+
+func TestStreamFlowInterceptorUsesRPCMethodAttribute(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(previous)
+
+	interceptor := NewStreamFlowInterceptor(StreamFlowOptions{})
+	stream := &blockingServerStream{release: make(chan struct{})}
+	close(stream.release)
+	if err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/example.Service/Watch"}, func(srv any, ss grpc.ServerStream) error {
+		return ss.SendMsg(wrapperspb.String("payload"))
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("collect metrics failed: %v", err)
+	}
+	messages := findSum(t, data, "rpc.server.stream.messages")
+	if len(messages.DataPoints) != 1 {
+		t.Fatalf("unexpected message data points: %+v", messages.DataPoints)
+	}
+	if got, _ := messages.DataPoints[0].Attributes.Value(attribute.Key(metricAttrRPCMethod)); got.AsString() != "/example.Service/Watch" {
+		t.Fatalf("expected rpc.method attribute, got %q", got.AsString())
+	}
+}
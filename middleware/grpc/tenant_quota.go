@@ -0,0 +1,214 @@
+package gm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTenantQuotaWindow 是租户配额的默认统计窗口。
+const DefaultTenantQuotaWindow = time.Minute
+
+// QuotaStore 定义配额计数存储，单实例可使用 NewMemoryQuotaStore，多实例共享配额时可基于 Redis 实现。
+type QuotaStore interface {
+	// Take 在 key 当前固定窗口内消耗一次额度，返回是否允许以及距离窗口重置的时长。
+	Take(ctx context.Context, key string, limit int64, window time.Duration) (allowed bool, resetAfter time.Duration, err error)
+}
+
+// TenantQuotaOptions 定义按租户的请求配额配置。
+type TenantQuotaOptions struct {
+	// Store 表示配额计数存储；为空时使用进程内存储，只在单实例内生效。
+	Store QuotaStore
+	// Limit 表示每个租户在一个窗口内的默认请求上限；非正数表示默认不限制。
+	Limit int64
+	// TenantLimits 按租户 ID 覆盖 Limit；值为非正数表示该租户不限制。
+	TenantLimits map[string]int64
+	// Window 表示统计窗口；为 0 时使用 DefaultTenantQuotaWindow。
+	Window time.Duration
+	// SkipMethods 表示不计入配额的 full method；gRPC health check 始终不计入。
+	SkipMethods []string
+	// FailClosed 为 true 时存储不可用直接返回 Unavailable；默认放行，避免配额存储故障拖垮业务。
+	FailClosed bool
+}
+
+func (o TenantQuotaOptions) normalize() TenantQuotaOptions {
+	if o.Store == nil {
+		o.Store = NewMemoryQuotaStore()
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultTenantQuotaWindow
+	}
+	return o
+}
+
+// tenantQuota 持有预处理后的配额规则。
+type tenantQuota struct {
+	options TenantQuotaOptions
+	skip    map[string]struct{}
+}
+
+func newTenantQuota(options TenantQuotaOptions) *tenantQuota {
+	return &tenantQuota{
+		options: options.normalize(),
+		skip:    buildMethodSet(options.SkipMethods),
+	}
+}
+
+// limit 返回租户生效的上限。
+func (q *tenantQuota) limit(tenantId string) int64 {
+	if limit, ok := q.options.TenantLimits[tenantId]; ok {
+		return limit
+	}
+	return q.options.Limit
+}
+
+// take 为当前请求所属租户消耗一次额度；无租户或不限制时直接放行。
+func (q *tenantQuota) take(ctx context.Context, method string) error {
+	if _, ok := q.skip[method]; ok || isHealthMethod(method) {
+		return nil
+	}
+	tenantId := tenantIdFromContext(ctx)
+	if tenantId == "" {
+		return nil
+	}
+	limit := q.limit(tenantId)
+	if limit <= 0 {
+		return nil
+	}
+
+	allowed, resetAfter, err := q.options.Store.Take(ctx, tenantId, limit, q.options.Window)
+	if err != nil {
+		if q.options.FailClosed {
+			return status.Error(codes.Unavailable, "tenant quota store unavailable")
+		}
+		return nil
+	}
+	if !allowed {
+		// 配额在窗口重置后恢复，提示调用方在 resetAfter 之后重试。
+		return rpcerror.WithRetryHint(
+			status.Error(codes.ResourceExhausted, "tenant quota exceeded"),
+			rpcerror.RetryHint{Retryable: true, RetryAfter: resetAfter},
+		)
+	}
+	return nil
+}
+
+// tenantIdFromContext 优先读取入口注入的 service.Context，缺失时回退到入站 metadata。
+func tenantIdFromContext(ctx context.Context) string {
+	if serviceContext, ok := service.FromContext(ctx); ok && serviceContext.TenantId != "" {
+		return serviceContext.TenantId
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return parseLogMetaKey(md, constant.TenantId)
+}
+
+// subjectFromContext 返回当前请求的认证主体标识，用于按主体隔离的缓存与去重 key；
+// 用户主体为 "user:<user_id>"，服务主体为 "service:<app_id>"，匿名请求为空字符串。
+func subjectFromContext(ctx context.Context) string {
+	var userId, subjectType, invokeAppId string
+	if serviceContext, ok := service.FromContext(ctx); ok {
+		userId, subjectType, invokeAppId = serviceContext.UserId, serviceContext.SubjectType, serviceContext.InvokeAppId
+	} else {
+		md, _ := metadata.FromIncomingContext(ctx)
+		userId = parseLogMetaKey(md, constant.UserId)
+		subjectType = parseLogMetaKey(md, constant.SubjectType)
+		invokeAppId = parseLogMetaKey(md, constant.InvokeAppId)
+	}
+	switch {
+	case userId != "":
+		return "user:" + userId
+	case subjectType == constant.SubjectTypeService && invokeAppId != "":
+		return "service:" + invokeAppId
+	default:
+		return ""
+	}
+}
+
+// NewTenantQuotaUnaryInterceptor 创建按租户的请求配额 unary 拦截器。
+//
+// 租户 ID 取自 service.Context（需放在 NewServiceContextUnaryInterceptor 之后），
+// 超出配额时返回 ResourceExhausted，并通过重试提示告知窗口重置时间。
+func NewTenantQuotaUnaryInterceptor(options TenantQuotaOptions) grpc.UnaryServerInterceptor {
+	quota := newTenantQuota(options)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := quota.take(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewTenantQuotaStreamInterceptor 创建按租户的请求配额 stream 拦截器，每个流只计一次。
+func NewTenantQuotaStreamInterceptor(options TenantQuotaOptions) grpc.StreamServerInterceptor {
+	quota := newTenantQuota(options)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := quota.take(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// memoryQuotaWindow 记录某个 key 当前窗口的起点与计数。
+type memoryQuotaWindow struct {
+	start time.Time
+	count int64
+}
+
+// MemoryQuotaStore 是进程内固定窗口配额存储。
+type MemoryQuotaStore struct {
+	mu      sync.Mutex
+	windows map[string]*memoryQuotaWindow
+	now     func() time.Time
+	// lastSweep 记录最近一次清理过期窗口的时间，避免长期不活跃的租户占用内存。
+	lastSweep time.Time
+}
+
+// NewMemoryQuotaStore 创建进程内配额存储。
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{
+		windows: make(map[string]*memoryQuotaWindow),
+		now:     time.Now,
+	}
+}
+
+// Take 实现 QuotaStore。
+func (s *MemoryQuotaStore) Take(_ context.Context, key string, limit int64, window time.Duration) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= window {
+		s.sweep(now, window)
+	}
+
+	current, ok := s.windows[key]
+	if !ok || now.Sub(current.start) >= window {
+		current = &memoryQuotaWindow{start: now}
+		s.windows[key] = current
+	}
+	resetAfter := current.start.Add(window).Sub(now)
+	if current.count >= limit {
+		return false, resetAfter, nil
+	}
+	current.count++
+	return true, resetAfter, nil
+}
+
+// sweep 清理已经过期的窗口。
+func (s *MemoryQuotaStore) sweep(now time.Time, window time.Duration) {
+	for key, current := range s.windows {
+		if now.Sub(current.start) >= window {
+			delete(s.windows, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,91 @@
+package gm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/rpcerror"
+	"github.com/fireflycore/go-micro/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewTenantQuotaUnaryInterceptorRejectsOverBudget(t *testing.T) {
+	store := NewMemoryQuotaStore()
+	now := time.Unix(1710000000, 0)
+	store.now = func() time.Time { return now }
+	interceptor := NewTenantQuotaUnaryInterceptor(TenantQuotaOptions{
+		Store:        store,
+		Limit:        2,
+		TenantLimits: map[string]int64{"vip": 0},
+		Window:       time.Minute,
+	})
+	call := func(ctx context.Context) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+			func(ctx context.Context, req any) (any, error) { return "ok", nil })
+		return err
+	}
+
+	tenantCtx := service.WithContext(context.Background(), &service.Context{TenantId: "t-1"})
+	for i := 0; i < 2; i++ {
+		if err := call(tenantCtx); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	err := call(tenantCtx)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	if hint, ok := rpcerror.RetryHintFromError(err); !ok || !hint.Retryable || hint.RetryAfter != time.Minute {
+		t.Fatalf("unexpected retry hint: %+v", hint)
+	}
+
+	// 其它租户与不限制的租户互不影响。
+	otherCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constant.TenantId, "t-2"))
+	if err := call(otherCtx); err != nil {
+		t.Fatalf("expected other tenant to pass, got %v", err)
+	}
+	vipCtx := service.WithContext(context.Background(), &service.Context{TenantId: "vip"})
+	for i := 0; i < 5; i++ {
+		if err := call(vipCtx); err != nil {
+			t.Fatalf("expected unlimited tenant to pass, got %v", err)
+		}
+	}
+
+	// 窗口重置后恢复额度。
+	now = now.Add(time.Minute)
+	if err := call(tenantCtx); err != nil {
+		t.Fatalf("expected quota to reset, got %v", err)
+	}
+}
+
+func TestNewTenantQuotaUnaryInterceptorStoreFailure(t *testing.T) {
+	ctx := service.WithContext(context.Background(), &service.Context{TenantId: "t-1"})
+	for _, failClosed := range []bool{false, true} {
+		interceptor := NewTenantQuotaUnaryInterceptor(TenantQuotaOptions{
+			Store:      failingQuotaStore{},
+			Limit:      1,
+			FailClosed: failClosed,
+		})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"},
+			func(ctx context.Context, req any) (any, error) { return "ok", nil })
+		if failClosed && status.Code(err) != codes.Unavailable {
+			t.Fatalf("expected Unavailable when fail closed, got %v", err)
+		}
+		if !failClosed && err != nil {
+			t.Fatalf("expected fail open, got %v", err)
+		}
+	}
+}
+
+// failingQuotaStore 始终返回存储错误。
+type failingQuotaStore struct{}
+
+func (failingQuotaStore) Take(context.Context, string, int64, time.Duration) (bool, time.Duration, error) {
+	return false, 0, errors.New("store down")
+}
@@ -0,0 +1,138 @@
+package gm
+
+import (
+	"context"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"github.com/fireflycore/go-micro/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// DeadlinePolicy 定义入站请求未携带 deadline 时的处理方式。
+type DeadlinePolicy int
+
+const (
+	// DeadlineOptional 表示不要求调用方携带 deadline，仅按默认超时补齐。
+	DeadlineOptional DeadlinePolicy = iota
+	// DeadlineWarn 表示输出一条 warn 日志后按默认超时补齐，用于灰度排查未设置超时的调用方。
+	DeadlineWarn
+	// DeadlineReject 表示直接返回 InvalidArgument，拒绝无界请求进入服务网格。
+	DeadlineReject
+)
+
+// ServerTimeoutOptions 定义服务端默认超时配置。
+type ServerTimeoutOptions struct {
+	// Default 表示调用方未携带 deadline 时施加的默认超时；非正数表示不施加。
+	Default time.Duration
+	// Methods 表示按 full method 覆盖的默认超时，优先于 Default。
+	Methods map[string]time.Duration
+	// RequireDeadline 表示调用方未携带 deadline 时的处理策略，默认 DeadlineOptional。
+	RequireDeadline DeadlinePolicy
+	// SkipMethods 表示不检查 deadline 的完整方法名；健康检查默认跳过。
+	SkipMethods []string
+	// Logger 在 DeadlineWarn / DeadlineReject 策略下记录缺少 deadline 的调用，为空时不输出。
+	Logger *logger.ServerLogger
+}
+
+// timeoutFor 返回指定方法的超时配置。
+func (o ServerTimeoutOptions) timeoutFor(method string) time.Duration {
+	if timeout, ok := o.Methods[method]; ok {
+		return timeout
+	}
+	return o.Default
+}
+
+// NewServerTimeoutUnaryInterceptor 创建服务端默认超时 unary 拦截器。
+//
+// 调用方未携带 deadline 时按方法施加默认超时，避免失控请求无限期占用连接、数据库等资源；
+// 调用方已携带 deadline 时保持不变，由调用方决定预算。
+// RequireDeadline 可进一步要求调用方显式携带 deadline：警告模式记录日志后继续，拒绝模式直接返回 InvalidArgument。
+func NewServerTimeoutUnaryInterceptor(options ServerTimeoutOptions) grpc.UnaryServerInterceptor {
+	skip := buildMethodSet(options.SkipMethods)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			method := unaryFullMethod(info)
+			if _, skipped := skip[method]; !skipped && !isHealthMethod(method) && options.RequireDeadline != DeadlineOptional {
+				logMissingDeadline(ctx, options, method)
+				if options.RequireDeadline == DeadlineReject {
+					return nil, status.Error(codes.InvalidArgument, "request deadline is required")
+				}
+			}
+			if timeout := options.timeoutFor(method); timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// logMissingDeadline 记录未携带 deadline 的入站调用，便于定位调用方。
+func logMissingDeadline(ctx context.Context, options ServerTimeoutOptions, method string) {
+	if options.Logger == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("path", method),
+		zap.Bool("rejected", options.RequireDeadline == DeadlineReject),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer_addr", p.Addr.String()))
+	}
+	options.Logger.WithContextWarn(ctx, constant.GrpcMissingDeadlineLog, fields...)
+}
+
+// ClientTimeoutOptions 定义客户端出站 deadline 上限配置。
+type ClientTimeoutOptions struct {
+	// Default 表示出站调用没有 deadline 时附加的默认超时；非正数时退回 Max。
+	Default time.Duration
+	// Max 表示出站调用允许的最长超时；非正数表示不限制。
+	Max time.Duration
+	// Methods 表示按 full method 覆盖的最长超时，优先于 Max。
+	Methods map[string]time.Duration
+}
+
+// maxFor 返回指定方法的最长超时。
+func (o ClientTimeoutOptions) maxFor(method string) time.Duration {
+	if timeout, ok := o.Methods[method]; ok {
+		return timeout
+	}
+	return o.Max
+}
+
+// defaultFor 返回指定方法在缺少 deadline 时附加的超时，受上限约束。
+func (o ClientTimeoutOptions) defaultFor(method string) time.Duration {
+	limit := o.maxFor(method)
+	if o.Default <= 0 || (limit > 0 && o.Default > limit) {
+		return limit
+	}
+	return o.Default
+}
+
+// NewClientTimeoutInterceptor 创建客户端 deadline unary 拦截器。
+//
+// 出站调用没有 deadline 时附加 Default（未配置时取上限），保证请求不会无界地在服务间传播；
+// 已有 deadline 晚于上限时收紧到上限，已有更短的 deadline 保持不变。
+func NewClientTimeoutInterceptor(options ClientTimeoutOptions) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		timeout := time.Duration(0)
+		if deadline, ok := ctx.Deadline(); !ok {
+			timeout = options.defaultFor(method)
+		} else if limit := options.maxFor(method); limit > 0 && time.Until(deadline) > limit {
+			timeout = limit
+		}
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,128 @@
+package gm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerTimeoutUnaryInterceptorAppliesDefaultOnlyWithoutDeadline(t *testing.T) {
+	interceptor := NewServerTimeoutUnaryInterceptor(ServerTimeoutOptions{
+		Default: time.Second,
+		Methods: map[string]time.Duration{"/example.Service/Export": time.Minute},
+	})
+
+	remaining := func(ctx context.Context, method string) time.Duration {
+		var got time.Duration
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatalf("expected deadline for %s", method)
+			}
+			got = time.Until(deadline)
+			return nil, nil
+		})
+		return got
+	}
+
+	if got := remaining(context.Background(), "/example.Service/Get"); got > time.Second || got < 500*time.Millisecond {
+		t.Fatalf("unexpected default timeout: %s", got)
+	}
+	if got := remaining(context.Background(), "/example.Service/Export"); got < 50*time.Second {
+		t.Fatalf("unexpected method timeout: %s", got)
+	}
+	// 调用方已携带 deadline 时保持不变。
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if got := remaining(ctx, "/example.Service/Get"); got < 5*time.Second {
+		t.Fatalf("expected caller deadline to be kept, got %s", got)
+	}
+}
+
+func TestClientTimeoutInterceptorCapsDeadline(t *testing.T) {
+	interceptor := NewClientTimeoutInterceptor(ClientTimeoutOptions{Max: time.Second})
+
+	remaining := func(ctx context.Context) time.Duration {
+		var got time.Duration
+		_ = interceptor(ctx, "/example.Service/Get", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			deadline, _ := ctx.Deadline()
+			got = time.Until(deadline)
+			return nil
+		})
+		return got
+	}
+
+	if got := remaining(context.Background()); got > time.Second || got <= 0 {
+		t.Fatalf("expected missing deadline to be capped, got %s", got)
+	}
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+	if got := remaining(long); got > time.Second {
+		t.Fatalf("expected long deadline to be capped, got %s", got)
+	}
+	short, cancelShort := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelShort()
+	if got := remaining(short); got > 100*time.Millisecond {
+		t.Fatalf("expected shorter deadline to be kept, got %s", got)
+	}
+}
+
+func TestServerTimeoutUnaryInterceptorRequireDeadline(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	reject := NewServerTimeoutUnaryInterceptor(ServerTimeoutOptions{RequireDeadline: DeadlineReject})
+	if _, err := reject(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"}, handler); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without deadline, got %v", err)
+	}
+	if _, err := reject(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: grpcHealthCheckFullMethod}, handler); err != nil {
+		t.Fatalf("expected health check to be exempt, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := reject(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"}, handler); err != nil {
+		t.Fatalf("expected request with deadline to pass, got %v", err)
+	}
+
+	// 警告模式不拒绝，并按默认超时补齐 deadline。
+	warn := NewServerTimeoutUnaryInterceptor(ServerTimeoutOptions{Default: time.Second, RequireDeadline: DeadlineWarn})
+	_, err := warn(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/example.Service/Get"}, func(ctx context.Context, req any) (any, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatal("expected default deadline in warn mode")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("expected warn mode to pass, got %v", err)
+	}
+}
+
+func TestClientTimeoutInterceptorAttachesDefault(t *testing.T) {
+	interceptor := NewClientTimeoutInterceptor(ClientTimeoutOptions{Default: 200 * time.Millisecond, Max: time.Second})
+
+	remaining := func(ctx context.Context) time.Duration {
+		var got time.Duration
+		_ = interceptor(ctx, "/example.Service/Get", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("expected deadline to be attached")
+			}
+			got = time.Until(deadline)
+			return nil
+		})
+		return got
+	}
+
+	if got := remaining(context.Background()); got > 200*time.Millisecond || got <= 0 {
+		t.Fatalf("expected default deadline, got %s", got)
+	}
+	// 已有 deadline 时不使用 Default，只受上限约束。
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if got := remaining(ctx); got <= 200*time.Millisecond {
+		t.Fatalf("expected caller deadline to be kept, got %s", got)
+	}
+}
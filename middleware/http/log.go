@@ -12,6 +12,7 @@ import (
 
 	"github.com/fireflycore/go-micro/constant"
 	"github.com/fireflycore/go-micro/logger"
+	"github.com/fireflycore/go-micro/rpcerror"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +26,8 @@ func NewAccessLogger(log *logger.AccessLogger) func(next http.Handler) http.Hand
 			}
 
 			start := time.Now()
+			// 安装请求级注解容器，业务 handler 通过 logger.Annotate 追加的字段会自动写入本条访问日志。
+			request = request.WithContext(logger.WithAnnotations(request.Context()))
 
 			var req []byte
 			if request.Method != http.MethodGet {
@@ -85,6 +88,11 @@ func NewAccessLogger(log *logger.AccessLogger) func(next http.Handler) http.Hand
 				zap.String("client_ip", request.Header.Get(constant.XRealIp)),
 			)
 
+			// 错误请求附带粗粒度错误类别，便于错误看板按类别聚合。
+			if class := rpcerror.ClassifyHTTPStatus(status); class != "" {
+				fields = append(fields, zap.String("error_class", class))
+			}
+
 			if status >= 500 {
 				log.WithContextError(request.Context(), constant.HttpAccessLog, fields...)
 			} else if status >= 400 {
@@ -0,0 +1,222 @@
+package hm
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+)
+
+// DefaultSecurityHeaders 是网关对浏览器响应默认追加的安全头。
+var DefaultSecurityHeaders = map[string]string{
+	"X-Content-Type-Options":    "nosniff",
+	"X-Frame-Options":           "DENY",
+	"Referrer-Policy":           "strict-origin-when-cross-origin",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+}
+
+// CORSOptions 定义跨域响应头策略。
+type CORSOptions struct {
+	// AllowOrigins 表示允许的来源；包含 "*" 时允许任意来源。
+	AllowOrigins []string
+	// AllowMethods 表示预检请求允许的方法。
+	AllowMethods []string
+	// AllowHeaders 表示预检请求允许的请求头。
+	AllowHeaders []string
+	// ExposeHeaders 表示允许浏览器读取的响应头。
+	ExposeHeaders []string
+	// AllowCredentials 表示是否允许携带凭证；开启时不会回写 "*"，而是回写具体 Origin。
+	AllowCredentials bool
+	// MaxAge 表示预检结果缓存时长。
+	MaxAge time.Duration
+}
+
+// CacheControlRule 定义按路径前缀设置的 Cache-Control。
+type CacheControlRule struct {
+	// PathPrefix 表示匹配的路径前缀，多条规则匹配时取最长前缀。
+	PathPrefix string
+	// Value 表示写入的 Cache-Control 值。
+	Value string
+}
+
+// ResponseHeaderPolicy 定义网关统一响应头策略。
+type ResponseHeaderPolicy struct {
+	// StripPrefixes 表示需要从响应中移除的 header 前缀；nil 时默认移除 x-firefly-*。
+	StripPrefixes []string
+	// SecurityHeaders 表示需要追加的安全头；nil 时使用 DefaultSecurityHeaders，空 map 表示不追加。
+	SecurityHeaders map[string]string
+	// CORS 表示跨域策略；nil 时不处理跨域。
+	CORS *CORSOptions
+	// CacheControl 表示按路径设置的 Cache-Control 规则；业务已写入时不覆盖。
+	CacheControl []CacheControlRule
+}
+
+// normalize 补齐默认值。
+func (p ResponseHeaderPolicy) normalize() ResponseHeaderPolicy {
+	if p.StripPrefixes == nil {
+		p.StripPrefixes = []string{constant.HeaderPrefix}
+	}
+	if p.SecurityHeaders == nil {
+		p.SecurityHeaders = DefaultSecurityHeaders
+	}
+	return p
+}
+
+// NewResponseHeaderPolicy 创建响应头策略中间件。
+//
+// 策略在业务第一次写出响应头前生效，保证内部 metadata 不会泄露给浏览器。
+func NewResponseHeaderPolicy(policy ResponseHeaderPolicy) func(next http.Handler) http.Handler {
+	policy = policy.normalize()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// CORS 预检请求直接由网关应答，不进入业务 handler。
+			if policy.CORS != nil && isPreflight(request) {
+				policy.apply(writer.Header(), request)
+				writer.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(&policyResponseWriter{ResponseWriter: writer, policy: &policy, request: request}, request)
+		})
+	}
+}
+
+// apply 把策略写入响应头。
+func (p *ResponseHeaderPolicy) apply(header http.Header, request *http.Request) {
+	// 1. 移除内部 header。
+	for key := range header {
+		lower := strings.ToLower(key)
+		for _, prefix := range p.StripPrefixes {
+			if strings.HasPrefix(lower, prefix) {
+				header.Del(key)
+				break
+			}
+		}
+	}
+
+	// 2. 追加安全头，业务已显式设置的值优先。
+	for key, value := range p.SecurityHeaders {
+		if header.Get(key) == "" {
+			header.Set(key, value)
+		}
+	}
+
+	// 3. 跨域头。
+	if p.CORS != nil {
+		p.CORS.apply(header, request)
+	}
+
+	// 4. 按路径设置 Cache-Control。
+	if header.Get("Cache-Control") == "" {
+		if value, ok := matchCacheControl(p.CacheControl, request.URL.Path); ok {
+			header.Set("Cache-Control", value)
+		}
+	}
+}
+
+// apply 写入跨域响应头。
+func (c *CORSOptions) apply(header http.Header, request *http.Request) {
+	origin := request.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+
+	allowed, wildcard := false, false
+	for _, item := range c.AllowOrigins {
+		if item == "*" {
+			allowed, wildcard = true, true
+			break
+		}
+		if strings.EqualFold(item, origin) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return
+	}
+
+	if wildcard && !c.AllowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(c.ExposeHeaders) != 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposeHeaders, ", "))
+	}
+
+	if isPreflight(request) {
+		if len(c.AllowMethods) != 0 {
+			header.Set("Access-Control-Allow-Methods", strings.Join(c.AllowMethods, ", "))
+		}
+		if len(c.AllowHeaders) != 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(c.AllowHeaders, ", "))
+		}
+		if c.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+		}
+	}
+}
+
+// isPreflight 判断是否为 CORS 预检请求。
+func isPreflight(request *http.Request) bool {
+	return request.Method == http.MethodOptions &&
+		request.Header.Get("Origin") != "" &&
+		request.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// matchCacheControl 按最长路径前缀匹配 Cache-Control 规则。
+func matchCacheControl(rules []CacheControlRule, path string) (string, bool) {
+	matched, length := "", -1
+	for _, rule := range rules {
+		if strings.HasPrefix(path, rule.PathPrefix) && len(rule.PathPrefix) > length {
+			matched, length = rule.Value, len(rule.PathPrefix)
+		}
+	}
+	return matched, length >= 0
+}
+
+// policyResponseWriter 在第一次写出响应头前应用响应头策略。
+type policyResponseWriter struct {
+	http.ResponseWriter
+	policy  *ResponseHeaderPolicy
+	request *http.Request
+	applied bool
+}
+
+func (w *policyResponseWriter) applyOnce() {
+	if w.applied {
+		return
+	}
+	w.applied = true
+	w.policy.apply(w.ResponseWriter.Header(), w.request)
+}
+
+func (w *policyResponseWriter) WriteHeader(code int) {
+	w.applyOnce()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *policyResponseWriter) Write(b []byte) (int, error) {
+	w.applyOnce()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *policyResponseWriter) Flush() {
+	w.applyOnce()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter。
+func (w *policyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
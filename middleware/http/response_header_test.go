@@ -0,0 +1,76 @@
+package hm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+)
+
+func TestResponseHeaderPolicyStripsInternalHeaders(t *testing.T) {
+	handler := NewResponseHeaderPolicy(ResponseHeaderPolicy{
+		CacheControl: []CacheControlRule{
+			{PathPrefix: "/", Value: "no-store"},
+			{PathPrefix: "/static/", Value: "public, max-age=3600"},
+		},
+	})(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set(constant.DecisionId, "decision")
+		writer.Header().Set("X-Frame-Options", "SAMEORIGIN")
+		_, _ = writer.Write([]byte("ok"))
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/static/app.js", nil))
+
+	if recorder.Header().Get(constant.DecisionId) != "" {
+		t.Fatal("expected internal header to be stripped")
+	}
+	if got := recorder.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+		t.Fatalf("expected handler value to win, got %q", got)
+	}
+	if got := recorder.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Fatalf("expected default security header, got %q", got)
+	}
+	if got := recorder.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Fatalf("expected longest prefix cache-control, got %q", got)
+	}
+}
+
+func TestResponseHeaderPolicyHandlesPreflight(t *testing.T) {
+	called := false
+	handler := NewResponseHeaderPolicy(ResponseHeaderPolicy{
+		SecurityHeaders: map[string]string{},
+		CORS: &CORSOptions{
+			AllowOrigins:     []string{"https://app.example.com"},
+			AllowMethods:     []string{http.MethodGet, http.MethodPost},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		},
+	})(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		called = true
+	}))
+
+	request := httptest.NewRequest(http.MethodOptions, "/api", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if called {
+		t.Fatal("expected preflight to be answered by middleware")
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("unexpected allow origin: %q", got)
+	}
+	if got := recorder.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Fatalf("unexpected max age: %q", got)
+	}
+	if recorder.Header().Get("X-Content-Type-Options") != "" {
+		t.Fatal("expected empty security headers to disable defaults")
+	}
+}
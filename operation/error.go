@@ -0,0 +1,19 @@
+// Package operation 定义长耗时操作（Long-Running Operation）的统一约定。
+package operation
+
+import "errors"
+
+var (
+	// ErrOperationIdEmpty 表示操作 ID 为空，无法定位具体操作。
+	ErrOperationIdEmpty = errors.New("operation id is empty")
+	// ErrOperationNotFound 表示操作不存在或已经被清理。
+	ErrOperationNotFound = errors.New("operation not found")
+	// ErrOperationExists 表示同一个操作 ID 已经存在，不能重复创建。
+	ErrOperationExists = errors.New("operation already exists")
+	// ErrOperationIsNil 表示传入的操作对象为空。
+	ErrOperationIsNil = errors.New("operation is nil")
+	// ErrRunFuncIsNil 表示启动操作时没有提供实际执行函数。
+	ErrRunFuncIsNil = errors.New("operation run function is nil")
+	// ErrServiceIsNil 表示轮询时没有提供可查询的操作服务。
+	ErrServiceIsNil = errors.New("operation service is nil")
+)
@@ -0,0 +1,153 @@
+package operation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Service 定义平台统一的操作查询接口。
+//
+// 服务端由 Manager 直接实现；客户端通常用生成的 gRPC stub 包一层适配，
+// 这样 Wait 轮询逻辑在两侧都可以复用。
+type Service interface {
+	// Get 返回指定操作的当前快照。
+	Get(ctx context.Context, id string) (*Operation, error)
+	// Cancel 请求取消指定操作；已结束的操作保持原状态。
+	Cancel(ctx context.Context, id string) error
+}
+
+// ReportFunc 表示执行过程中上报进度的回调。
+type ReportFunc func(progress uint32, message string)
+
+// RunFunc 表示长耗时操作的实际执行函数。
+//
+// ctx 在 Cancel 时会被取消；返回的 result 会写入 Operation.Result。
+type RunFunc func(ctx context.Context, report ReportFunc) ([]byte, error)
+
+// Manager 负责在服务端启动、跟踪和取消长耗时操作。
+type Manager struct {
+	// store 保存操作状态。
+	store Store
+	// mu 保护 cancels，避免并发 Start/Cancel 产生竞态。
+	mu sync.Mutex
+	// cancels 保存仍在执行中的操作取消函数。
+	cancels map[string]context.CancelFunc
+	// now 返回当前时间，便于测试替换。
+	now func() time.Time
+}
+
+// NewManager 创建操作管理器；store 为空时使用进程内 MemoryStore。
+func NewManager(store Store) *Manager {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Manager{
+		store:   store,
+		cancels: make(map[string]context.CancelFunc),
+		now:     time.Now,
+	}
+}
+
+// Start 创建一个新操作并在后台执行 run，立即返回初始快照。
+//
+// 执行上下文会继承 ctx 中的 value（trace、service.Context 等），
+// 但不会随请求结束而取消，只会被 Cancel 取消。
+func (m *Manager) Start(ctx context.Context, method string, run RunFunc) (*Operation, error) {
+	if run == nil {
+		return nil, ErrRunFuncIsNil
+	}
+
+	now := m.now()
+	op := &Operation{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		Method:    method,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.store.Create(ctx, op); err != nil {
+		return nil, err
+	}
+
+	// 脱离请求生命周期，但保留上下文中的链路信息。
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.mu.Lock()
+	m.cancels[op.Id] = cancel
+	m.mu.Unlock()
+
+	go m.run(runCtx, op.Clone(), run)
+
+	return op, nil
+}
+
+// run 在后台执行操作，并把进度和最终结果写回存储。
+func (m *Manager) run(ctx context.Context, op *Operation, run RunFunc) {
+	defer m.release(op.Id)
+
+	// opMu 保护 op，避免业务在其它协程中调用 report 时与最终写回竞争。
+	var opMu sync.Mutex
+	report := func(progress uint32, message string) {
+		opMu.Lock()
+		defer opMu.Unlock()
+		// 操作已结束后不再接受进度上报。
+		if op.Done {
+			return
+		}
+		if progress > 100 {
+			progress = 100
+		}
+		op.Progress = progress
+		op.Message = message
+		op.UpdatedAt = m.now()
+		_ = m.store.Update(ctx, op)
+	}
+
+	result, err := run(ctx, report)
+	// 被取消的操作统一记录为 Canceled，避免业务返回的包装错误丢失语义。
+	if err != nil && ctx.Err() != nil {
+		err = status.Error(codes.Canceled, ctx.Err().Error())
+	}
+
+	opMu.Lock()
+	defer opMu.Unlock()
+	op.finish(result, err, m.now())
+	// 最终状态使用独立 ctx 写回，保证取消后仍能落库。
+	_ = m.store.Update(context.WithoutCancel(ctx), op)
+}
+
+// release 清理已经结束的操作取消函数。
+func (m *Manager) release(id string) {
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	delete(m.cancels, id)
+	m.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// Get 返回指定操作的当前快照。
+func (m *Manager) Get(ctx context.Context, id string) (*Operation, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Cancel 请求取消指定操作。
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	// 先确认操作存在，避免对未知 ID 静默成功。
+	if _, err := m.store.Get(ctx, id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	m.mu.Unlock()
+	// 已结束的操作没有取消函数，直接保持原状态。
+	if ok {
+		cancel()
+	}
+	return nil
+}
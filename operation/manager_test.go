@@ -0,0 +1,152 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestManagerStartAndWait(t *testing.T) {
+	manager := NewManager(nil)
+
+	op, err := manager.Start(context.Background(), "/acme.report.v1.ReportService/Export", func(ctx context.Context, report ReportFunc) ([]byte, error) {
+		report(50, "half")
+		return []byte("done"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	if op.Id == "" || op.Done {
+		t.Fatalf("unexpected initial operation: %+v", op)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	final, err := Wait(ctx, manager, op.Id, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	if !final.Done || final.Progress != 100 || string(final.Result) != "done" {
+		t.Fatalf("unexpected final operation: %+v", final)
+	}
+	if final.Err() != nil {
+		t.Fatalf("expected no operation error, got %v", final.Err())
+	}
+}
+
+func TestManagerRecordsFailure(t *testing.T) {
+	manager := NewManager(nil)
+
+	op, err := manager.Start(context.Background(), "", func(ctx context.Context, report ReportFunc) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	final, err := Wait(ctx, manager, op.Id, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	if codes.Code(final.ErrorCode) != codes.Unknown || final.ErrorMessage != "boom" {
+		t.Fatalf("unexpected failed operation: %+v", final)
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	manager := NewManager(nil)
+	started := make(chan struct{})
+
+	op, err := manager.Start(context.Background(), "", func(ctx context.Context, report ReportFunc) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	<-started
+
+	if err := manager.Cancel(context.Background(), op.Id); err != nil {
+		t.Fatalf("unexpected cancel error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	final, err := Wait(ctx, manager, op.Id, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected wait error: %v", err)
+	}
+	if codes.Code(final.ErrorCode) != codes.Canceled {
+		t.Fatalf("expected canceled operation, got %+v", final)
+	}
+}
+
+func TestManagerCancelUnknownOperation(t *testing.T) {
+	manager := NewManager(nil)
+	if err := manager.Cancel(context.Background(), "missing"); !errors.Is(err, ErrOperationNotFound) {
+		t.Fatalf("expected ErrOperationNotFound, got %v", err)
+	}
+}
+
+func TestOperationIdMetadata(t *testing.T) {
+	outgoing := AppendToOutgoingContext(context.Background(), "op-1")
+	md, _ := metadata.FromOutgoingContext(outgoing)
+	if got := md.Get(constant.OperationId); len(got) != 1 || got[0] != "op-1" {
+		t.Fatalf("unexpected outgoing operation id: %v", got)
+	}
+
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+	if got := IdFromIncomingContext(incoming); got != "op-1" {
+		t.Fatalf("unexpected incoming operation id: %q", got)
+	}
+}
+
+func TestStreamSendsOnlyChangedSnapshots(t *testing.T) {
+	manager := NewManager(nil)
+	// 缓冲足够容纳所有非结束快照，避免轮询时序导致发送阻塞。
+	step := make(chan struct{}, 3)
+
+	op, err := manager.Start(context.Background(), "", func(ctx context.Context, report ReportFunc) ([]byte, error) {
+		report(30, "step-1")
+		<-step
+		report(60, "step-2")
+		<-step
+		return []byte("ok"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	var sent []*Operation
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	final, err := Stream(ctx, manager, op.Id, time.Millisecond, func(snapshot *Operation) error {
+		sent = append(sent, snapshot)
+		if !snapshot.Done {
+			step <- struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if !final.Done {
+		t.Fatalf("expected final snapshot to be done, got %+v", final)
+	}
+	for i := 1; i < len(sent); i++ {
+		if !progressChanged(sent[i-1], sent[i]) {
+			t.Fatalf("expected only changed snapshots, got duplicate at %d: %+v", i, sent[i])
+		}
+	}
+	if last := sent[len(sent)-1]; !last.Done || last.Progress != 100 {
+		t.Fatalf("unexpected last snapshot: %+v", last)
+	}
+}
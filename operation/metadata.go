@@ -0,0 +1,38 @@
+package operation
+
+import (
+	"context"
+
+	"github.com/fireflycore/go-micro/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AppendToOutgoingContext 把操作 ID 写入出站 metadata，供下游关联同一个操作。
+func AppendToOutgoingContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constant.OperationId, id)
+}
+
+// IdFromIncomingContext 从入站 metadata 读取操作 ID；不存在时返回空字符串。
+func IdFromIncomingContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(constant.OperationId)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// SetHeader 在服务端响应 header 中写入操作 ID，便于网关和客户端无需解析响应体即可拿到 ID。
+func SetHeader(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrOperationIdEmpty
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(constant.OperationId, id))
+}
@@ -0,0 +1,74 @@
+package operation
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Operation 表示一次长耗时操作的当前快照。
+//
+// 慢 RPC 不再同步阻塞到完成，而是立即返回 Operation，
+// 调用方再通过 Service.Get 轮询，直到 Done 为 true。
+type Operation struct {
+	// Id 表示操作唯一标识，跨进程通过 x-firefly-operation-id 传播。
+	Id string `json:"id"`
+	// Method 表示发起该操作的 gRPC FullMethod，便于排障和审计。
+	Method string `json:"method"`
+	// Done 表示操作是否已经结束；结束后 Result 或错误字段二选一有效。
+	Done bool `json:"done"`
+	// Progress 表示完成百分比，取值 0-100。
+	Progress uint32 `json:"progress"`
+	// Message 表示当前进度的简短描述。
+	Message string `json:"message,omitempty"`
+	// Result 保存操作成功后的结果报文，具体编码由业务方约定。
+	Result []byte `json:"result,omitempty"`
+	// ErrorCode 保存操作失败时的 gRPC code；成功或未结束时为 0。
+	ErrorCode uint32 `json:"error_code,omitempty"`
+	// ErrorMessage 保存操作失败时的错误描述。
+	ErrorMessage string `json:"error_message,omitempty"`
+	// CreatedAt 表示操作创建时间。
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt 表示操作最近一次状态变更时间。
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Err 返回操作失败时对应的 gRPC status error；未失败时返回 nil。
+func (o *Operation) Err() error {
+	// 空操作或没有错误码时都视为无错误。
+	if o == nil || codes.Code(o.ErrorCode) == codes.OK {
+		return nil
+	}
+	// 统一还原成 gRPC status，调用方可以直接用 status.Code 判断。
+	return status.Error(codes.Code(o.ErrorCode), o.ErrorMessage)
+}
+
+// Clone 返回操作的深拷贝，避免调用方修改存储内部状态。
+func (o *Operation) Clone() *Operation {
+	if o == nil {
+		return nil
+	}
+	cloned := *o
+	// Result 是切片，需要单独复制底层数组。
+	if o.Result != nil {
+		cloned.Result = append([]byte(nil), o.Result...)
+	}
+	return &cloned
+}
+
+// finish 把操作标记为结束，并按 err 写入结果或错误字段。
+func (o *Operation) finish(result []byte, err error, now time.Time) {
+	o.Done = true
+	o.UpdatedAt = now
+	if err != nil {
+		// 非 gRPC 错误统一按 Unknown 处理，保持错误码可读。
+		st := status.Convert(err)
+		o.ErrorCode = uint32(st.Code())
+		o.ErrorMessage = st.Message()
+		return
+	}
+	// 成功结束时进度固定为 100。
+	o.Progress = 100
+	o.Result = result
+}
@@ -0,0 +1,97 @@
+package operation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultPollInterval 是轮询操作状态的默认初始间隔。
+	DefaultPollInterval = 500 * time.Millisecond
+	// DefaultPollMaxInterval 是轮询操作状态的默认最大间隔。
+	DefaultPollMaxInterval = 10 * time.Second
+	// DefaultPollMultiplier 是每次轮询后的间隔放大倍数。
+	DefaultPollMultiplier = 2
+)
+
+// PollOptions 定义客户端轮询操作状态时的退避策略。
+type PollOptions struct {
+	// Interval 表示首次轮询间隔；未设置时使用 DefaultPollInterval。
+	Interval time.Duration
+	// MaxInterval 表示退避上限；未设置时使用 DefaultPollMaxInterval。
+	MaxInterval time.Duration
+	// Multiplier 表示每次轮询后的间隔放大倍数；小于 1 时使用 DefaultPollMultiplier。
+	Multiplier float64
+	// OnProgress 非空时，每次拿到新快照都会回调一次，便于调用方展示进度。
+	OnProgress func(op *Operation)
+}
+
+// normalize 补齐 PollOptions 的默认值。
+func (o PollOptions) normalize() PollOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultPollInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultPollMaxInterval
+	}
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = DefaultPollMultiplier
+	}
+	return o
+}
+
+// Wait 按指数退避轮询指定操作，直到操作结束或 ctx 结束。
+//
+// 返回的 error 只表示轮询本身失败；操作执行失败需要通过 Operation.Err 读取。
+func Wait(ctx context.Context, service Service, id string, options PollOptions) (*Operation, error) {
+	return poll(ctx, service, id, options, func(op *Operation) error {
+		if options.OnProgress != nil {
+			options.OnProgress(op)
+		}
+		return nil
+	})
+}
+
+// poll 是 Wait 与 Stream 共用的轮询主循环，每拿到一次快照都会回调 onSnapshot。
+func poll(ctx context.Context, service Service, id string, options PollOptions, onSnapshot func(op *Operation) error) (*Operation, error) {
+	if service == nil {
+		return nil, ErrServiceIsNil
+	}
+	if id == "" {
+		return nil, ErrOperationIdEmpty
+	}
+
+	options = options.normalize()
+	interval := options.Interval
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		op, err := service.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := onSnapshot(op); err != nil {
+			return nil, err
+		}
+		if op.Done {
+			return op, nil
+		}
+
+		timer.Reset(interval)
+		// 下一次间隔按倍数放大，但不超过上限。
+		interval = time.Duration(float64(interval) * options.Multiplier)
+		if interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package operation
+
+import (
+	"context"
+	"sync"
+)
+
+// Store 定义服务端保存操作状态的存储抽象。
+//
+// 默认提供进程内 MemoryStore；多副本部署时业务方可以基于 Redis、数据库等实现同一接口，
+// 让任意副本都能响应 Get 查询。
+type Store interface {
+	// Create 写入一个新操作；ID 已存在时返回 ErrOperationExists。
+	Create(ctx context.Context, op *Operation) error
+	// Get 按 ID 读取操作快照；不存在时返回 ErrOperationNotFound。
+	Get(ctx context.Context, id string) (*Operation, error)
+	// Update 覆盖写入已有操作；不存在时返回 ErrOperationNotFound。
+	Update(ctx context.Context, op *Operation) error
+	// Delete 删除指定操作，通常用于结果被取走后的清理。
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore 是基于进程内 map 的 Store 实现。
+type MemoryStore struct {
+	// mu 保护 operations，后台执行协程与查询请求会并发访问。
+	mu sync.RWMutex
+	// operations 按操作 ID 保存快照。
+	operations map[string]*Operation
+}
+
+// NewMemoryStore 创建进程内操作存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		operations: make(map[string]*Operation),
+	}
+}
+
+// Create 写入一个新操作。
+func (s *MemoryStore) Create(_ context.Context, op *Operation) error {
+	if op == nil {
+		return ErrOperationIsNil
+	}
+	if op.Id == "" {
+		return ErrOperationIdEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.operations[op.Id]; ok {
+		return ErrOperationExists
+	}
+	// 保存副本，避免调用方继续修改入参影响存储。
+	s.operations[op.Id] = op.Clone()
+	return nil
+}
+
+// Get 按 ID 读取操作快照。
+func (s *MemoryStore) Get(_ context.Context, id string) (*Operation, error) {
+	if id == "" {
+		return nil, ErrOperationIdEmpty
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	op, ok := s.operations[id]
+	if !ok {
+		return nil, ErrOperationNotFound
+	}
+	// 返回副本，避免调用方修改内部状态。
+	return op.Clone(), nil
+}
+
+// Update 覆盖写入已有操作。
+func (s *MemoryStore) Update(_ context.Context, op *Operation) error {
+	if op == nil {
+		return ErrOperationIsNil
+	}
+	if op.Id == "" {
+		return ErrOperationIdEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.operations[op.Id]; !ok {
+		return ErrOperationNotFound
+	}
+	s.operations[op.Id] = op.Clone()
+	return nil
+}
+
+// Delete 删除指定操作；不存在时直接返回 nil，保持删除幂等。
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	if id == "" {
+		return ErrOperationIdEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.operations, id)
+	return nil
+}
@@ -0,0 +1,122 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultStreamInterval 是进度流轮询操作状态的默认固定间隔。
+	DefaultStreamInterval = time.Second
+
+	// sseEventProgress 表示 SSE 中的进度事件名。
+	sseEventProgress = "progress"
+	// sseEventDone 表示 SSE 中的结束事件名。
+	sseEventDone = "done"
+	// sseEventError 表示 SSE 中轮询失败时的错误事件名。
+	sseEventError = "error"
+)
+
+// SendFunc 表示把一次进度快照推送给调用方的函数，通常是 server stream 的 Send 包装。
+type SendFunc func(op *Operation) error
+
+// Stream 把一个 unary LRO 适配为进度推送流。
+//
+// 它按固定间隔轮询 Service，只在进度、描述或结束状态发生变化时调用 send，
+// 操作结束后返回最终快照。适用于 server-streaming 进度 RPC：
+//
+//	return operation.Stream(stream.Context(), manager, req.Id, 0, func(op *operation.Operation) error {
+//		return stream.Send(toProgressResponse(op))
+//	})
+func Stream(ctx context.Context, service Service, id string, interval time.Duration, send SendFunc) (*Operation, error) {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+
+	// 进度流需要稳定的刷新频率，因此不做指数退避。
+	options := PollOptions{
+		Interval:    interval,
+		MaxInterval: interval,
+		Multiplier:  1,
+	}
+
+	var last *Operation
+	return poll(ctx, service, id, options, func(op *Operation) error {
+		// 快照没有变化时不重复推送，避免刷屏。
+		if !progressChanged(last, op) {
+			return nil
+		}
+		last = op
+		if send == nil {
+			return nil
+		}
+		return send(op)
+	})
+}
+
+// progressChanged 判断两次快照之间是否存在调用方可感知的变化。
+func progressChanged(last *Operation, current *Operation) bool {
+	if last == nil {
+		return true
+	}
+	return last.Progress != current.Progress || last.Message != current.Message || last.Done != current.Done
+}
+
+// NewSSEHandler 返回把操作进度以 HTTP Server-Sent Events 输出的 handler。
+//
+// 操作 ID 通过 idFunc 从请求中解析；每次进度变化输出一条 progress 事件，
+// 结束时输出 done 事件，轮询失败时输出 error 事件。
+func NewSSEHandler(service Service, interval time.Duration, idFunc func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		flusher, ok := writer.(http.Flusher)
+		if !ok {
+			http.Error(writer, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		id := ""
+		if idFunc != nil {
+			id = idFunc(request)
+		}
+		if id == "" {
+			http.Error(writer, ErrOperationIdEmpty.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.Header().Set("Cache-Control", "no-cache")
+		writer.Header().Set("Connection", "keep-alive")
+		writer.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		_, err := Stream(request.Context(), service, id, interval, func(op *Operation) error {
+			event := sseEventProgress
+			if op.Done {
+				event = sseEventDone
+			}
+			if err := writeSSEEvent(writer, event, op); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		// 客户端主动断开时无需再写错误事件。
+		if err != nil && request.Context().Err() == nil {
+			_ = writeSSEEvent(writer, sseEventError, map[string]string{"message": err.Error()})
+			flusher.Flush()
+		}
+	})
+}
+
+// writeSSEEvent 按 SSE 格式写出一条事件。
+func writeSSEEvent(writer http.ResponseWriter, event string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
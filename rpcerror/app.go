@@ -0,0 +1,72 @@
+package rpcerror
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+// MessageKeyInternal 是未知错误对外展示时使用的消息 key。
+const MessageKeyInternal = "internal_error"
+
+// AppError 是业务错误的统一约定：携带 gRPC code 与可本地化的消息 key，
+// 由服务端错误映射拦截器转换为带 details 的 status，避免把原始 Go 错误字符串返回给客户端。
+type AppError interface {
+	error
+	// GRPCCode 返回对外的 gRPC code。
+	GRPCCode() codes.Code
+	// MessageKey 返回用于查找本地化文案的消息 key，例如 order.not_found。
+	MessageKey() string
+	// MessageArgs 返回填充本地化文案模板的参数。
+	MessageArgs() []any
+}
+
+// Error 是 AppError 的默认实现。
+type Error struct {
+	// Code 表示对外的 gRPC code。
+	Code codes.Code
+	// Key 表示本地化消息 key。
+	Key string
+	// Args 表示文案模板参数。
+	Args []any
+	// Cause 表示内部原因，只用于日志与 errors.Is/As，不会返回给客户端。
+	Cause error
+}
+
+// New 创建业务错误。
+func New(code codes.Code, key string, args ...any) *Error {
+	return &Error{Code: code, Key: key, Args: args}
+}
+
+// Wrap 创建携带内部原因的业务错误。
+func Wrap(cause error, code codes.Code, key string, args ...any) *Error {
+	return &Error{Code: code, Key: key, Args: args, Cause: cause}
+}
+
+// Error 实现 error，包含内部原因便于日志排查。
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Key, e.Cause)
+	}
+	return e.Key
+}
+
+// Unwrap 返回内部原因。
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// GRPCCode 实现 AppError。
+func (e *Error) GRPCCode() codes.Code {
+	return e.Code
+}
+
+// MessageKey 实现 AppError。
+func (e *Error) MessageKey() string {
+	return e.Key
+}
+
+// MessageArgs 实现 AppError。
+func (e *Error) MessageArgs() []any {
+	return e.Args
+}
@@ -0,0 +1,57 @@
+package rpcerror
+
+import "google.golang.org/grpc/codes"
+
+const (
+	// ClassClient 表示调用方请求本身有问题，重试同样的请求通常不会成功。
+	ClassClient = "client"
+	// ClassServer 表示服务端或依赖故障。
+	ClassServer = "server"
+	// ClassTimeout 表示调用超过截止时间。
+	ClassTimeout = "timeout"
+	// ClassCancelled 表示调用方主动取消。
+	ClassCancelled = "cancelled"
+)
+
+// Classify 把 gRPC code 归类为粗粒度错误类别，便于错误看板按类别聚合；codes.OK 返回空字符串。
+func Classify(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return ""
+	case codes.DeadlineExceeded:
+		return ClassTimeout
+	case codes.Canceled:
+		return ClassCancelled
+	case codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.Unauthenticated,
+		codes.FailedPrecondition,
+		codes.OutOfRange,
+		codes.ResourceExhausted,
+		codes.Aborted,
+		codes.Unimplemented:
+		return ClassClient
+	default:
+		// Unknown / Internal / Unavailable / DataLoss 以及未来新增 code 都按服务端错误处理。
+		return ClassServer
+	}
+}
+
+// ClassifyHTTPStatus 把 HTTP 状态码归类为粗粒度错误类别；非错误状态返回空字符串。
+func ClassifyHTTPStatus(status int) string {
+	switch {
+	case status == 408 || status == 504:
+		return ClassTimeout
+	case status == 499:
+		// 499 是 Nginx 约定的客户端关闭连接。
+		return ClassCancelled
+	case status >= 500:
+		return ClassServer
+	case status >= 400:
+		return ClassClient
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,14 @@
+// Package rpcerror 定义跨服务传递的结构化 gRPC 错误约定。
+package rpcerror
+
+// Domain 是 Firefly 写入 errdetails.ErrorInfo 的统一 domain。
+const Domain = "firefly"
+
+const (
+	// MetadataRetryable 表示 ErrorInfo.metadata 中承载是否可重试的 key，取值为 "true" / "false"。
+	MetadataRetryable = "retryable"
+	// MetadataUpstream 表示 ErrorInfo.metadata 中承载错误来源上游服务名的 key。
+	MetadataUpstream = "upstream"
+	// MetadataMessageKey 表示 ErrorInfo.metadata 中承载业务错误消息 key 的 key。
+	MetadataMessageKey = "message_key"
+)
@@ -0,0 +1,66 @@
+package rpcerror
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Localizer 根据语言与消息 key 生成对外文案。
+type Localizer interface {
+	// Localize 返回文案与实际使用的语言；找不到 key 时第三个返回值为 false。
+	Localize(language string, key string, args ...any) (message string, locale string, ok bool)
+}
+
+// Catalog 是基于内存表的 Localizer，结构为 语言 -> 消息 key -> fmt 模板。
+//
+// 查找顺序：完整语言（zh-CN）→ 主语言（zh）→ Fallback。
+type Catalog struct {
+	// Messages 表示按语言组织的文案模板。
+	Messages map[string]map[string]string
+	// Fallback 表示请求语言缺失对应文案时使用的语言。
+	Fallback string
+}
+
+// Localize 实现 Localizer。
+func (c *Catalog) Localize(language string, key string, args ...any) (string, string, bool) {
+	if c == nil {
+		return "", "", false
+	}
+	for _, candidate := range languageCandidates(language, c.Fallback) {
+		template, ok := c.Messages[candidate][key]
+		if !ok {
+			continue
+		}
+		if len(args) == 0 {
+			return template, candidate, true
+		}
+		return fmt.Sprintf(template, args...), candidate, true
+	}
+	return "", "", false
+}
+
+// languageCandidates 返回按优先级排列且去重的候选语言。
+func languageCandidates(language string, fallback string) []string {
+	candidates := make([]string, 0, 3)
+	add := func(value string) {
+		if value == "" {
+			return
+		}
+		for _, existing := range candidates {
+			if existing == value {
+				return
+			}
+		}
+		candidates = append(candidates, value)
+	}
+	// Accept-Language 风格的值只取第一个偏好，并去掉权重。
+	language, _, _ = strings.Cut(language, ",")
+	language, _, _ = strings.Cut(language, ";")
+	language = strings.TrimSpace(language)
+	add(language)
+	if base, _, found := strings.Cut(language, "-"); found {
+		add(base)
+	}
+	add(fallback)
+	return candidates
+}
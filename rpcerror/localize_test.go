@@ -0,0 +1,25 @@
+package rpcerror
+
+import "testing"
+
+func TestCatalogLocalize(t *testing.T) {
+	catalog := &Catalog{
+		Messages: map[string]map[string]string{
+			"zh": {"order.not_found": "订单 %s 不存在"},
+			"en": {"order.not_found": "order %s not found", "order.locked": "order is locked"},
+		},
+		Fallback: "en",
+	}
+
+	message, locale, ok := catalog.Localize("zh-CN,en;q=0.8", "order.not_found", "o-1")
+	if !ok || message != "订单 o-1 不存在" || locale != "zh" {
+		t.Fatalf("unexpected base language match: %q %q %v", message, locale, ok)
+	}
+	message, locale, ok = catalog.Localize("zh-CN", "order.locked")
+	if !ok || message != "order is locked" || locale != "en" {
+		t.Fatalf("unexpected fallback match: %q %q %v", message, locale, ok)
+	}
+	if _, _, ok := catalog.Localize("zh-CN", "order.unknown"); ok {
+		t.Fatal("expected missing key to report not found")
+	}
+}
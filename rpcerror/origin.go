@@ -0,0 +1,67 @@
+package rpcerror
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// OriginLocal 表示错误由当前服务自身产生。
+	OriginLocal = "local"
+	// OriginUpstream 表示错误来自当前服务调用的下游服务，被原样或包装后返回。
+	OriginUpstream = "upstream"
+)
+
+// MarkUpstream 在错误 details 中标记其来自下游服务 upstream。
+//
+// 标记写在 status details 中，业务 handler 原样返回该错误时，访问日志仍能区分本地错误与上游错误。
+// 非 status error 或已经标记过的错误保持不变。
+func MarkUpstream(err error, upstream string) error {
+	st, ok := status.FromError(err)
+	if !ok || st == nil || st.Code() == codes.OK {
+		return err
+	}
+	if _, marked := UpstreamFromError(err); marked {
+		return err
+	}
+
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   st.Code().String(),
+		Domain:   Domain,
+		Metadata: map[string]string{MetadataUpstream: upstream},
+	})
+	if detailErr != nil {
+		return err
+	}
+	return withDetails.Err()
+}
+
+// UpstreamFromError 读取错误的上游来源标记；未标记时第二个返回值为 false。
+func UpstreamFromError(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return "", false
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+		if upstream, exists := info.GetMetadata()[MetadataUpstream]; exists {
+			return upstream, true
+		}
+	}
+	return "", false
+}
+
+// Origin 返回错误来源：OriginUpstream 或 OriginLocal；err 为 nil 时返回空字符串。
+func Origin(err error) string {
+	if err == nil {
+		return ""
+	}
+	if _, ok := UpstreamFromError(err); ok {
+		return OriginUpstream
+	}
+	return OriginLocal
+}
@@ -0,0 +1,64 @@
+package rpcerror
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMarkUpstream(t *testing.T) {
+	err := MarkUpstream(status.Error(codes.NotFound, "user not found"), "auth")
+	if status.Code(err) != codes.NotFound || status.Convert(err).Message() != "user not found" {
+		t.Fatalf("expected code and message to be preserved, got %v", err)
+	}
+	if upstream, ok := UpstreamFromError(err); !ok || upstream != "auth" {
+		t.Fatalf("unexpected upstream: %q %v", upstream, ok)
+	}
+	if Origin(err) != OriginUpstream {
+		t.Fatalf("expected upstream origin, got %q", Origin(err))
+	}
+
+	// 重复标记保留最初的上游来源。
+	if upstream, _ := UpstreamFromError(MarkUpstream(err, "gateway")); upstream != "auth" {
+		t.Fatalf("expected first upstream to win, got %q", upstream)
+	}
+
+	plain := errors.New("plain")
+	if MarkUpstream(plain, "auth") != plain {
+		t.Fatal("expected non status error to stay unchanged")
+	}
+	if Origin(status.Error(codes.Internal, "boom")) != OriginLocal {
+		t.Fatal("expected unmarked error to be local")
+	}
+}
+
+func TestMarkUpstreamKeepsRetryHint(t *testing.T) {
+	err := WithRetryHint(status.Error(codes.Unavailable, "busy"), RetryHint{Retryable: true, RetryAfter: time.Second})
+	err = MarkUpstream(err, "order")
+
+	if retryable, after := IsRetryable(err); !retryable || after != time.Second {
+		t.Fatalf("expected retry hint to survive upstream marking, got %v %v", retryable, after)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[codes.Code]string{
+		codes.OK:               "",
+		codes.NotFound:         ClassClient,
+		codes.Internal:         ClassServer,
+		codes.Unavailable:      ClassServer,
+		codes.DeadlineExceeded: ClassTimeout,
+		codes.Canceled:         ClassCancelled,
+	}
+	for code, expected := range cases {
+		if got := Classify(code); got != expected {
+			t.Fatalf("code %v: expected %q, got %q", code, expected, got)
+		}
+	}
+	if ClassifyHTTPStatus(404) != ClassClient || ClassifyHTTPStatus(504) != ClassTimeout || ClassifyHTTPStatus(200) != "" {
+		t.Fatal("unexpected http status classification")
+	}
+}
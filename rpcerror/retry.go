@@ -0,0 +1,119 @@
+package rpcerror
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryHint 表示服务端给调用方的机器可读重试提示。
+type RetryHint struct {
+	// Retryable 表示本次失败是否允许调用方重试。
+	Retryable bool
+	// RetryAfter 表示建议的最小重试间隔；只在 Retryable 为 true 时有意义，0 表示由调用方自行退避。
+	RetryAfter time.Duration
+}
+
+// DefaultRetryableCodes 是服务端没有给出重试提示时，调用方默认视为可重试的 gRPC code。
+var DefaultRetryableCodes = []codes.Code{codes.Unavailable}
+
+// WithRetryHint 把重试提示写入错误的 status details。
+//
+// 写入规则：
+// - 始终追加 ErrorInfo{domain=firefly, metadata.retryable=true/false}，显式表达是否可重试；
+// - 可重试且 RetryAfter > 0 时额外追加标准 errdetails.RetryInfo，其他语言客户端也能识别。
+//
+// err 为 nil 时返回 nil；非 status error 会按 codes.Unknown 转换。
+func WithRetryHint(err error, hint RetryHint) error {
+	if err == nil {
+		return nil
+	}
+
+	st := status.Convert(err)
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   st.Code().String(),
+			Domain:   Domain,
+			Metadata: map[string]string{MetadataRetryable: strconv.FormatBool(hint.Retryable)},
+		},
+	}
+	if hint.Retryable && hint.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(hint.RetryAfter)})
+	}
+
+	withDetails, detailErr := st.WithDetails(details...)
+	if detailErr != nil {
+		// OK 状态无法携带 details，此时保持原错误不变。
+		return err
+	}
+	return withDetails.Err()
+}
+
+// RetryHintFromError 从错误 details 中读取重试提示；没有任何提示时第二个返回值为 false。
+func RetryHintFromError(err error) (RetryHint, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return RetryHint{}, false
+	}
+
+	var (
+		hint  RetryHint
+		found bool
+	)
+	for _, detail := range st.Details() {
+		switch value := detail.(type) {
+		case *errdetails.RetryInfo:
+			// 标准 RetryInfo 本身就表示可重试。
+			hint.Retryable = true
+			hint.RetryAfter = value.GetRetryDelay().AsDuration()
+			found = true
+		case *errdetails.ErrorInfo:
+			if value.GetDomain() != Domain {
+				continue
+			}
+			raw, exists := value.GetMetadata()[MetadataRetryable]
+			if !exists {
+				continue
+			}
+			retryable, parseErr := strconv.ParseBool(raw)
+			if parseErr != nil {
+				continue
+			}
+			// ErrorInfo 的显式 false 优先于其他来源的可重试提示。
+			if !retryable {
+				return RetryHint{}, true
+			}
+			hint.Retryable = true
+			found = true
+		}
+	}
+	return hint, found
+}
+
+// IsRetryable 判断错误是否可以重试，并返回建议的最小重试间隔。
+//
+// 服务端给出重试提示时以提示为准；否则按 retryableCodes 分类，为空时使用 DefaultRetryableCodes。
+func IsRetryable(err error, retryableCodes ...codes.Code) (bool, time.Duration) {
+	if err == nil {
+		return false, 0
+	}
+	if hint, ok := RetryHintFromError(err); ok {
+		return hint.Retryable, hint.RetryAfter
+	}
+
+	if len(retryableCodes) == 0 {
+		retryableCodes = DefaultRetryableCodes
+	}
+	code := status.Code(err)
+	for _, item := range retryableCodes {
+		if item == code {
+			return true, 0
+		}
+	}
+	return false, 0
+}
@@ -0,0 +1,52 @@
+package rpcerror
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithRetryHintRoundTrip(t *testing.T) {
+	err := WithRetryHint(status.Error(codes.ResourceExhausted, "quota exceeded"), RetryHint{Retryable: true, RetryAfter: 3 * time.Second})
+
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected code to be preserved, got %v", status.Code(err))
+	}
+	hint, ok := RetryHintFromError(err)
+	if !ok || !hint.Retryable || hint.RetryAfter != 3*time.Second {
+		t.Fatalf("unexpected hint: %+v, %v", hint, ok)
+	}
+
+	retryable, after := IsRetryable(err)
+	if !retryable || after != 3*time.Second {
+		t.Fatalf("unexpected retryable result: %v %v", retryable, after)
+	}
+}
+
+func TestRetryHintOverridesCodeClassification(t *testing.T) {
+	err := WithRetryHint(status.Error(codes.Unavailable, "draining"), RetryHint{Retryable: false})
+	if retryable, _ := IsRetryable(err); retryable {
+		t.Fatal("expected explicit non-retryable hint to override Unavailable")
+	}
+}
+
+func TestIsRetryableFallsBackToCodes(t *testing.T) {
+	if retryable, _ := IsRetryable(status.Error(codes.Unavailable, "down")); !retryable {
+		t.Fatal("expected Unavailable to be retryable by default")
+	}
+	if retryable, _ := IsRetryable(status.Error(codes.Internal, "boom")); retryable {
+		t.Fatal("expected Internal to be non-retryable by default")
+	}
+	if retryable, _ := IsRetryable(status.Error(codes.Aborted, "conflict"), codes.Aborted); !retryable {
+		t.Fatal("expected custom code list to be honored")
+	}
+	if retryable, _ := IsRetryable(errors.New("plain")); retryable {
+		t.Fatal("expected plain error to be non-retryable")
+	}
+	if WithRetryHint(nil, RetryHint{Retryable: true}) != nil {
+		t.Fatal("expected nil error to stay nil")
+	}
+}
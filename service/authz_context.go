@@ -104,12 +104,22 @@ type AuthzUserContext struct {
 	RoleIds []string `json:"role_ids,omitempty"`
 }
 
+// PublicKeyResolver 表示按 kid 动态查找验签公钥的能力，例如带后台刷新的 JWKS 缓存。
+//
+// 实现必须是非阻塞的本地查询，不能在请求热路径上发起网络调用。
+type PublicKeyResolver interface {
+	// PublicKey 返回 kid 对应的 Ed25519 公钥；找不到时返回 false。
+	PublicKey(kid string) (ed25519.PublicKey, bool)
+}
+
 // AuthzSignVerificationOptions 定义服务侧本地验签 x-firefly-authz-sign 的规则。
 type AuthzSignVerificationOptions struct {
 	// PublicKey 是单公钥模式下的 Ed25519 公钥。
 	PublicKey ed25519.PublicKey
 	// PublicKeys 是按 kid 索引的 Ed25519 公钥集合，用于密钥轮换。
 	PublicKeys map[string]ed25519.PublicKey
+	// KeyResolver 非空时优先按 kid 动态查找公钥，未命中再回退 PublicKeys / PublicKey。
+	KeyResolver PublicKeyResolver
 	// Issuer 非空时要求 iss 必须与该值一致。
 	Issuer string
 	// ExpectedApiMethod 非空时要求 api_method 必须匹配当前入口授权动作。
@@ -211,6 +221,12 @@ func parseAuthzSignHeader(segment string) (*authzSignJWSHeader, error) {
 }
 
 func resolveAuthzSignPublicKey(kid string, options AuthzSignVerificationOptions) (ed25519.PublicKey, bool) {
+	// 动态解析器优先，便于 JWKS 轮换后无需重启即可接受新 kid。
+	if options.KeyResolver != nil {
+		if key, ok := options.KeyResolver.PublicKey(kid); ok && len(key) == ed25519.PublicKeySize {
+			return key, true
+		}
+	}
 	// 多公钥模式优先，便于 authz 密钥轮换期间同时接受新旧 kid。
 	if len(options.PublicKeys) > 0 {
 		// 通过 JWS header 中的 kid 找到对应公钥。
@@ -332,6 +332,55 @@ func TestVerifyAuthzSign_RejectsMissingMethod(t *testing.T) {
 	}
 }
 
+// staticKeyResolver 是测试用的固定公钥解析器。
+type staticKeyResolver map[string]ed25519.PublicKey
+
+func (r staticKeyResolver) PublicKey(kid string) (ed25519.PublicKey, bool) {
+	key, ok := r[kid]
+	return key, ok
+}
+
+func TestVerifyAuthzSign_UsesKeyResolver(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+
+	now := time.Unix(1710000000, 0).UTC()
+	raw := signTestAuthzSign(t, privateKey, "rotated", map[string]any{
+		"iss":           testAuthzIssuer,
+		"sub":           "user-1",
+		"subject_type":  constant.SubjectTypeUser,
+		"invoke_app_id": "user-app",
+		"target_app_id": "app-target",
+		"api_method":    constant.RequestMethodGrpcString,
+		"api_path":      "/acme.order.v1.OrderService/List",
+		"decision":      testAuthzDecisionAllow,
+		"decision_id":   "decision-1",
+		"user_context": map[string]any{
+			"user_id": "user-1",
+			"app_id":  "user-app",
+		},
+		"target_service_app_id": "app-target",
+		"iat":                   now.Unix(),
+		"exp":                   now.Add(time.Minute).Unix(),
+	})
+
+	// 静态公钥集合中没有新 kid，只能由动态解析器命中。
+	claims, err := VerifyAuthzSign(raw, AuthzSignVerificationOptions{
+		PublicKeys:  map[string]ed25519.PublicKey{testAuthzKid: publicKey},
+		KeyResolver: staticKeyResolver{"rotated": publicKey},
+		Issuer:      testAuthzIssuer,
+		Now:         func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("verify authz sign failed: %v", err)
+	}
+	if claims.KeyId != "rotated" {
+		t.Fatalf("unexpected key id: %s", claims.KeyId)
+	}
+}
+
 func signTestAuthzSign(t *testing.T, privateKey ed25519.PrivateKey, kid string, claims map[string]any) string {
 	t.Helper()
 
@@ -2,6 +2,13 @@ package service
 
 import "errors"
 
+const (
+	// DefaultWeight 表示未配置权重时使用的默认值。
+	DefaultWeight uint = 100
+	// MaxWeight 表示权重上限，超出时截断，避免单实例权重失衡导致流量倾斜。
+	MaxWeight uint = 10000
+)
+
 type Config struct {
 	// Name 表示业务服务名，例如 auth。
 	Name string `json:"name"`
@@ -13,7 +20,7 @@ type Config struct {
 	ClusterDomain string `json:"cluster_domain"`
 	// Port 表示业务服务监听端口，默认值通常为 9090。
 	Port uint `json:"port"`
-	// Weight 表示权重，默认值通常为 100。
+	// Weight 表示权重，取值范围为 1~MaxWeight，未配置时默认为 DefaultWeight。
 	Weight uint `json:"weight"`
 }
 
@@ -34,7 +41,10 @@ func (c *Config) Bootstrap() error {
 		c.Port = 9090
 	}
 	if c.Weight == 0 {
-		c.Weight = 100
+		c.Weight = DefaultWeight
+	}
+	if c.Weight > MaxWeight {
+		c.Weight = MaxWeight
 	}
 	return nil
 }
@@ -0,0 +1,20 @@
+package service
+
+import "testing"
+
+func TestConfigBootstrapNormalizesWeight(t *testing.T) {
+	cases := map[uint]uint{
+		0:             DefaultWeight,
+		50:            50,
+		MaxWeight + 1: MaxWeight,
+	}
+	for input, expected := range cases {
+		config := &Config{Name: "auth", Weight: input}
+		if err := config.Bootstrap(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Weight != expected {
+			t.Fatalf("weight %d: expected %d, got %d", input, expected, config.Weight)
+		}
+	}
+}
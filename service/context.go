@@ -12,6 +12,7 @@ type contextKey string
 
 const (
 	serviceContextValueKey contextKey = "service.context"
+	serviceShadowValueKey  contextKey = "service.shadow"
 )
 
 // Context 表示当前请求在服务进程内流转时的统一主上下文。
@@ -29,6 +30,8 @@ type Context struct {
 	ServiceInstanceId string
 	// AppLanguage 表示客户端应用语言偏好。
 	AppLanguage string
+	// ConsistencyToken 表示请求携带的读己之写一致性令牌，读路由据此判断只读副本是否足够新。
+	ConsistencyToken string
 	// Session 表示 authz 从用户 token 中解析出的会话标识。
 	Session string
 	// UserId 表示用户主体 ID；服务或匿名主体为空。
@@ -141,6 +144,46 @@ func MustFromContext(ctx context.Context) *Context {
 	return value
 }
 
+// UserFromContext 从 ctx 读取用户身份上下文。
+//
+// 服务或匿名主体、以及入口未注入 service.Context 时返回 false，业务代码无需再自行解析 metadata。
+func UserFromContext(ctx context.Context) (*UserContext, bool) {
+	value, ok := FromContext(ctx)
+	if !ok || value.UserContext == nil {
+		return nil, false
+	}
+	return value.UserContext, true
+}
+
+// DecisionFromContext 从 ctx 读取 authz 判定上下文；未注入或没有判定事实时返回 false。
+func DecisionFromContext(ctx context.Context) (*DecisionContext, bool) {
+	value, ok := FromContext(ctx)
+	if !ok || value.DecisionContext == nil {
+		return nil, false
+	}
+	return value.DecisionContext, true
+}
+
+// WithShadow 把当前请求标记为可信的镜像流量。
+//
+// 只应由入口 header 规范化（对端可信时）或流量镜像拦截器调用；入站 x-firefly-shadow 本身不可信，
+// 出站透传镜像标记也以该标记为准。
+func WithShadow(ctx context.Context) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, serviceShadowValueKey, true)
+}
+
+// IsShadow 判断当前请求是否为可信的镜像流量。
+func IsShadow(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	value, _ := ctx.Value(serviceShadowValueKey).(bool)
+	return value
+}
+
 // BuildContext 从入站 metadata 与运行时信息构造服务主上下文。
 //
 // 它只负责把服务端入口已经拿到的 metadata 与 OTel span 信息结构化，
@@ -200,6 +243,8 @@ func buildContextFromMetadata(ctx context.Context, options BuildContextOptions)
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		// AppLanguage 是客户端偏好字段，不参与权限判断。
 		value.AppLanguage = ParseMetaKey(md, constant.AppLanguage)
+		// ConsistencyToken 是不透明令牌，只用于读路由，不参与权限判断。
+		value.ConsistencyToken = ParseMetaKey(md, constant.ConsistencyToken)
 		// Session 来自 authz 对 token/session 的可信解析，不作为出站透传字段。
 		value.Session = ParseMetaKey(md, constant.Session)
 		// UserId 来自 authz allow 后注入的普通 metadata；服务主体通常为空。
@@ -16,6 +16,7 @@ import (
 func TestBuildContext(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
 		constant.AppLanguage, "zh-CN",
+		constant.ConsistencyToken, "lsn-42",
 		constant.Session, "session-1",
 		constant.UserId, "user-1",
 		constant.AppId, "app-1",
@@ -40,7 +41,7 @@ func TestBuildContext(t *testing.T) {
 	if value.UserId != "user-1" || value.AppId != "app-1" || value.TenantId != "tenant-1" {
 		t.Fatalf("unexpected identity fields: %+v", value)
 	}
-	if value.AppLanguage != "zh-CN" || value.Session != "session-1" {
+	if value.AppLanguage != "zh-CN" || value.Session != "session-1" || value.ConsistencyToken != "lsn-42" {
 		t.Fatalf("unexpected app context fields: %+v", value)
 	}
 	if value.SubjectType != constant.SubjectTypeUser || value.InvokeAppId != "app-1" || value.TargetAppId != "order-app" {
@@ -238,3 +239,30 @@ func TestWithContextAndFromContext(t *testing.T) {
 		t.Fatalf("unexpected user id: %+v", value)
 	}
 }
+
+func TestUserFromContext(t *testing.T) {
+	if _, ok := UserFromContext(context.Background()); ok {
+		t.Fatal("expected no user without service context")
+	}
+
+	// 服务主体没有用户身份，只有判定事实。
+	serviceCtx := WithContext(context.Background(), BuildContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		constant.SubjectType, constant.SubjectTypeService,
+		constant.InvokeAppId, "billing-app",
+	)), BuildContextOptions{}))
+	if _, ok := UserFromContext(serviceCtx); ok {
+		t.Fatal("expected no user for service subject")
+	}
+	if decision, ok := DecisionFromContext(serviceCtx); !ok || decision.InvokeAppId != "billing-app" {
+		t.Fatalf("unexpected decision context: %+v", decision)
+	}
+
+	userCtx := WithContext(context.Background(), BuildContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		constant.UserId, "user-1",
+		constant.TenantId, "tenant-1",
+	)), BuildContextOptions{}))
+	user, ok := UserFromContext(userCtx)
+	if !ok || user.UserId != "user-1" || user.TenantId != "tenant-1" {
+		t.Fatalf("unexpected user context: %+v", user)
+	}
+}
@@ -0,0 +1,138 @@
+// Package shutdown 提供按固定顺序优雅停机的通用流程。
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// DefaultTimeout 是整个停机流程的默认截止时间。
+const DefaultTimeout = 30 * time.Second
+
+// Func 表示停机流程中的一个步骤。
+type Func func(ctx context.Context) error
+
+// Options 定义优雅停机流程的参与方。
+//
+// 停机顺序固定为：
+// 1. Deregister：从注册中心 / sidecar-agent 摘除实例，让上游不再路由新流量；
+// 2. GRPCServer：GracefulStop 停止接收新请求并等待在途请求结束，截止时间到达时强制 Stop；
+// 3. Closers：按传入顺序释放 watcher、连接管理器、telemetry 等资源；
+// 4. Loggers：最后 Sync 日志，保证前面步骤产生的日志也能落盘。
+type Options struct {
+	// Timeout 表示整个停机流程的截止时间；未设置时使用 DefaultTimeout。
+	Timeout time.Duration
+	// Signals 表示触发停机的信号；未设置时监听 SIGTERM 与 SIGINT。
+	Signals []os.Signal
+	// Deregister 表示实例摘除动作，例如通知 sidecar-agent 下线。
+	Deregister Func
+	// GRPCServer 表示需要优雅关闭的 gRPC 服务端。
+	GRPCServer *grpc.Server
+	// Closers 表示需要在服务端关闭后依次释放的资源。
+	Closers []Func
+	// Loggers 表示需要在最后刷盘的 zap logger。
+	Loggers []*zap.Logger
+}
+
+// Wait 阻塞等待停机信号或 ctx 结束，然后执行 Run。
+//
+// 典型用法是在 main 中启动服务后调用：
+//
+//	go server.Serve(listener)
+//	if err := shutdown.Wait(context.Background(), options); err != nil {
+//		log.Error("shutdown", zap.Error(err))
+//	}
+func Wait(ctx context.Context, options Options) error {
+	signals := options.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, syscall.SIGINT}
+	}
+
+	notifyCtx, stop := signal.NotifyContext(ctx, signals...)
+	<-notifyCtx.Done()
+	stop()
+
+	// 停机流程不能继承已经结束的 ctx，否则所有步骤都会立即超时。
+	return Run(context.WithoutCancel(ctx), options)
+}
+
+// Run 立即按固定顺序执行停机流程，返回所有步骤的错误合集。
+func Run(ctx context.Context, options Options) error {
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out error
+
+	// 1. 先摘除实例，避免在服务端停止后仍有新流量被路由进来。
+	if options.Deregister != nil {
+		if err := options.Deregister(ctx); err != nil {
+			out = errors.Join(out, fmt.Errorf("shutdown deregister: %w", err))
+		}
+	}
+
+	// 2. 停止接收新请求并等待在途请求结束。
+	if options.GRPCServer != nil {
+		if err := stopGRPCServer(ctx, options.GRPCServer); err != nil {
+			out = errors.Join(out, err)
+		}
+	}
+
+	// 3. 服务端已无请求在处理，可以安全释放下游依赖。
+	for index, closer := range options.Closers {
+		if closer == nil {
+			continue
+		}
+		if err := closer(ctx); err != nil {
+			out = errors.Join(out, fmt.Errorf("shutdown closer %d: %w", index, err))
+		}
+	}
+
+	// 4. 最后刷盘日志，保证停机过程中的日志不丢失。
+	for _, logger := range options.Loggers {
+		if logger == nil {
+			continue
+		}
+		// stdout/stderr 在部分平台 Sync 会返回 EINVAL，这类错误没有处理意义。
+		if err := logger.Sync(); err != nil && !isIgnorableSyncError(err) {
+			out = errors.Join(out, fmt.Errorf("shutdown logger sync: %w", err))
+		}
+	}
+
+	return out
+}
+
+// stopGRPCServer 优雅关闭 gRPC 服务端，截止时间到达时强制关闭。
+func stopGRPCServer(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// 在途请求超过截止时间时强制断开，避免停机流程无限等待。
+		server.Stop()
+		<-done
+		return fmt.Errorf("shutdown grpc server: %w", ctx.Err())
+	}
+}
+
+// isIgnorableSyncError 判断 logger.Sync 返回的错误是否可以忽略。
+func isIgnorableSyncError(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EBADF)
+}
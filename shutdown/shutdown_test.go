@@ -0,0 +1,99 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func TestRunExecutesStepsInOrder(t *testing.T) {
+	var order []string
+	step := func(name string) Func {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	err := Run(context.Background(), Options{
+		Deregister: step("deregister"),
+		GRPCServer: grpc.NewServer(),
+		Closers:    []Func{step("unwatch"), nil, step("telemetry")},
+		Loggers:    []*zap.Logger{zap.NewNop(), nil},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"deregister", "unwatch", "telemetry"}
+	if len(order) != len(expected) {
+		t.Fatalf("unexpected step order: %v", order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("unexpected step order: %v", order)
+		}
+	}
+}
+
+func TestRunContinuesAfterStepFailure(t *testing.T) {
+	deregisterErr := errors.New("deregister failed")
+	closed := false
+
+	err := Run(context.Background(), Options{
+		Deregister: func(ctx context.Context) error { return deregisterErr },
+		Closers: []Func{func(ctx context.Context) error {
+			closed = true
+			return nil
+		}},
+	})
+	if !errors.Is(err, deregisterErr) {
+		t.Fatalf("expected deregister error, got %v", err)
+	}
+	if !closed {
+		t.Fatal("expected closers to run after deregister failure")
+	}
+}
+
+func TestRunStopsServingGRPCServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+
+	if err := Run(context.Background(), Options{GRPCServer: server, Timeout: time.Second}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("expected grpc server to stop serving")
+	}
+}
+
+func TestWaitRunsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := Wait(ctx, Options{Deregister: func(ctx context.Context) error {
+		called = true
+		// 停机步骤拿到的 ctx 不能继承已经取消的父 ctx。
+		return ctx.Err()
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected shutdown to run after context cancel")
+	}
+}
@@ -7,6 +7,9 @@ type Config struct {
 	OTLPEndpoint string `json:"otlp_endpoint"`
 	// Insecure 决定是否使用非安全连接 (HTTP/gRPC without TLS)。
 	Insecure bool `json:"insecure"`
+	// Compression 表示 OTLP 导出使用的压缩算法，当前支持 "gzip"；为空时不压缩。
+	// 高 QPS 服务开启后可显著降低批量导出日志与 span 的带宽。
+	Compression string `json:"compression"`
 
 	// Traces 开关，控制是否启用链路追踪。
 	Traces bool `json:"traces"`
@@ -8,6 +8,8 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
@@ -68,9 +70,17 @@ func NewProviders(config *Config, source *Resource) (*Providers, error) {
 	otlpEndpoint := config.OTLPEndpoint
 	insecure := config.Insecure
 
+	// 压缩只作用于 OTLP 导出，批量处理器本身已在后台异步聚合。
+	var traceOpts []otlptracegrpc.Option
+	var logOpts []otlploggrpc.Option
+	if config.Compression != "" {
+		traceOpts = append(traceOpts, otlptracegrpc.WithCompressor(config.Compression))
+		logOpts = append(logOpts, otlploggrpc.WithCompressor(config.Compression))
+	}
+
 	// 3. 初始化 Traces
 	if config.Traces {
-		tp, err := NewTracerProvider(ctx, res, otlpEndpoint, insecure)
+		tp, err := NewTracerProvider(ctx, res, otlpEndpoint, insecure, traceOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -93,7 +103,7 @@ func NewProviders(config *Config, source *Resource) (*Providers, error) {
 
 	// 5. 初始化 Logs
 	if config.Logs {
-		lp, err := NewLoggerProvider(ctx, res, otlpEndpoint, insecure)
+		lp, err := NewLoggerProvider(ctx, res, otlpEndpoint, insecure, logOpts...)
 		if err != nil {
 			return nil, err
 		}
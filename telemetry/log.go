@@ -15,18 +15,20 @@ import (
 //   - res: 资源属性（包含服务名、版本等）
 //   - otlpEndpoint: OTLP 收集器地址
 //   - insecure: 是否使用非安全连接
+//   - opts: 额外的导出器选项，例如 otlploggrpc.WithCompressor("gzip")
 //
 // 返回:
 //   - *sdklog.LoggerProvider: 配置好的 LoggerProvider
 //   - error: 初始化过程中的错误
-func NewLoggerProvider(ctx context.Context, res *resource.Resource, otlpEndpoint string, insecure bool) (*sdklog.LoggerProvider, error) {
-	expOpts := make([]otlploggrpc.Option, 0, 2)
+func NewLoggerProvider(ctx context.Context, res *resource.Resource, otlpEndpoint string, insecure bool, opts ...otlploggrpc.Option) (*sdklog.LoggerProvider, error) {
+	expOpts := make([]otlploggrpc.Option, 0, 2+len(opts))
 	if otlpEndpoint != "" {
 		expOpts = append(expOpts, otlploggrpc.WithEndpoint(otlpEndpoint))
 	}
 	if insecure {
 		expOpts = append(expOpts, otlploggrpc.WithInsecure())
 	}
+	expOpts = append(expOpts, opts...)
 
 	// 创建 OTLP Log Exporter
 	logExp, err := otlploggrpc.New(ctx, expOpts...)
@@ -15,18 +15,20 @@ import (
 //   - res: 资源属性（包含服务名、版本等）
 //   - otlpEndpoint: OTLP 收集器地址（例如 "localhost:4317"）
 //   - insecure: 是否使用非安全连接（HTTP/gRPC without TLS）
+//   - opts: 额外的导出器选项，例如 otlptracegrpc.WithCompressor("gzip")
 //
 // 返回:
 //   - *sdktrace.TracerProvider: 配置好的 TracerProvider
 //   - error: 初始化过程中的错误
-func NewTracerProvider(ctx context.Context, res *resource.Resource, otlpEndpoint string, insecure bool) (*sdktrace.TracerProvider, error) {
-	expOpts := make([]otlptracegrpc.Option, 0, 2)
+func NewTracerProvider(ctx context.Context, res *resource.Resource, otlpEndpoint string, insecure bool, opts ...otlptracegrpc.Option) (*sdktrace.TracerProvider, error) {
+	expOpts := make([]otlptracegrpc.Option, 0, 2+len(opts))
 	if otlpEndpoint != "" {
 		expOpts = append(expOpts, otlptracegrpc.WithEndpoint(otlpEndpoint))
 	}
 	if insecure {
 		expOpts = append(expOpts, otlptracegrpc.WithInsecure())
 	}
+	expOpts = append(expOpts, opts...)
 
 	// 创建 OTLP Trace Exporter
 	traceExp, err := otlptracegrpc.New(ctx, expOpts...)